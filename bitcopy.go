@@ -0,0 +1,91 @@
+package bitfield
+
+// ReadBits fills dst with the dst.Len() bits of src that start at the given bit offset, i.e. the bit
+// i of dst is set to the bit offset+i of src. Byte-backed bitfields are copied a byte at a time,
+// other implementations fall back to BitAt/SetBitAt.
+// This method will return an error if the bits to read do not fit within src.
+func ReadBits(dst, src Bitfield, offset uint64) error {
+	n := dst.Len()
+	if offset+n < offset || offset+n > src.Len() {
+		return indexOutOfRange("ReadBits", offset+n, src.Len())
+	}
+
+	dstBytes, err := bitfieldBytes("ReadBits", dst)
+	if err != nil {
+		return err
+	}
+	srcBytes, err := bitfieldBytes("ReadBits", src)
+	if err != nil {
+		return err
+	}
+	if dstBytes != nil && srcBytes != nil {
+		copyBits(dstBytes, 0, srcBytes, offset, n)
+		return nil
+	}
+
+	for i := uint64(0); i < n; i++ {
+		dst.SetBitAt(i, src.BitAt(offset+i))
+	}
+	return nil
+}
+
+// WriteBits copies all the bits of src into dst, starting at the given bit offset, i.e. the bit
+// offset+i of dst is set to the bit i of src. Byte-backed bitfields are copied a byte at a time,
+// other implementations fall back to BitAt/SetBitAt.
+// This method will return an error if the bits to write do not fit within dst.
+func WriteBits(dst Bitfield, offset uint64, src Bitfield) error {
+	n := src.Len()
+	if offset+n < offset || offset+n > dst.Len() {
+		return indexOutOfRange("WriteBits", offset+n, dst.Len())
+	}
+
+	dstBytes, err := bitfieldBytes("WriteBits", dst)
+	if err != nil {
+		return err
+	}
+	srcBytes, err := bitfieldBytes("WriteBits", src)
+	if err != nil {
+		return err
+	}
+	if dstBytes != nil && srcBytes != nil {
+		copyBits(dstBytes, offset, srcBytes, 0, n)
+		return nil
+	}
+
+	for i := uint64(0); i < n; i++ {
+		dst.SetBitAt(offset+i, src.BitAt(i))
+	}
+	return nil
+}
+
+// bitfieldBytes returns the underlying bytes of byte-backed bitfields, where the bit i is stored
+// as the bit i%8 of the byte i/8. For other implementations nil is returned. This method will
+// return an error if a bitvector is too short to hold its bits. The name of the calling method is
+// used for error reporting.
+func bitfieldBytes(name string, b Bitfield) ([]byte, error) {
+	var ret []byte
+	switch v := b.(type) {
+	case Bitlist:
+		return v, nil
+	case Bitvector4:
+		ret = v
+	case Bitvector8:
+		ret = v
+	case Bitvector32:
+		ret = v
+	case Bitvector64:
+		ret = v
+	case Bitvector128:
+		ret = v
+	case Bitvector256:
+		ret = v
+	case Bitvector512:
+		ret = v
+	default:
+		return nil, nil
+	}
+	if uint64(len(ret))*8 < b.Len() {
+		return nil, lengthMismatch(name, ErrWrongLen, uint64(len(ret)), (b.Len()+7)/8)
+	}
+	return ret, nil
+}
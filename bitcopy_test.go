@@ -0,0 +1,88 @@
+package bitfield
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestReadWriteBits(t *testing.T) {
+	tests := []struct {
+		large  Bitfield
+		small  func() Bitfield
+		offset uint64
+	}{
+		{large: NewBitvector512(), small: func() Bitfield { return NewBitvector128() }, offset: 0},
+		{large: NewBitvector512(), small: func() Bitfield { return NewBitvector128() }, offset: 384},
+		{large: NewBitvector512(), small: func() Bitfield { return NewBitvector64() }, offset: 13},
+		{large: NewBitvector256(), small: func() Bitfield { return NewBitvector4() }, offset: 250},
+		{large: NewBitvector32(), small: func() Bitfield { return NewBitvector8() }, offset: 3},
+		{large: NewBitlist(100), small: func() Bitfield { return NewBitvector32() }, offset: 67},
+		{large: NewBitlist64(300), small: func() Bitfield { return NewBitvector64() }, offset: 100},
+		{large: NewBitvector128(), small: func() Bitfield { return NewBitlist64(70) }, offset: 5},
+		{large: NewBitvector128(), small: func() Bitfield { return NewBitlist(9) }, offset: 119},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%T[%d:]<->%T", tt.large, tt.offset, tt.small()), func(t *testing.T) {
+			for i := uint64(0); i < tt.large.Len(); i++ {
+				tt.large.SetBitAt(i, i%3 == 0 || i%7 == 0)
+			}
+
+			small := tt.small()
+			if err := ReadBits(small, tt.large, tt.offset); err != nil {
+				t.Fatal(err)
+			}
+			for i := uint64(0); i < small.Len(); i++ {
+				if small.BitAt(i) != tt.large.BitAt(tt.offset+i) {
+					t.Fatalf("ReadBits(): bit %d = %t, wanted %t", i, small.BitAt(i), tt.large.BitAt(tt.offset+i))
+				}
+			}
+
+			// Flip the bits, and write them back into the large bitfield.
+			for i := uint64(0); i < small.Len(); i++ {
+				small.SetBitAt(i, !small.BitAt(i))
+			}
+			if err := WriteBits(tt.large, tt.offset, small); err != nil {
+				t.Fatal(err)
+			}
+			for i := uint64(0); i < tt.large.Len(); i++ {
+				want := i%3 == 0 || i%7 == 0
+				if i >= tt.offset && i < tt.offset+small.Len() {
+					want = !want
+				}
+				if tt.large.BitAt(i) != want {
+					t.Fatalf("WriteBits(): bit %d = %t, wanted %t", i, tt.large.BitAt(i), want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadWriteBits_Errors(t *testing.T) {
+	large := NewBitvector512()
+	if err := ReadBits(NewBitvector128(), large, 385); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+	}
+	if err := WriteBits(large, 385, NewBitvector128()); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+	}
+	if err := WriteBits(large, ^uint64(0), NewBitvector128()); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+	}
+	if err := ReadBits(Bitvector64{0x01}, large, 0); !errors.Is(err, ErrWrongLen) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+	}
+
+	// The length bit of a bitlist is never overwritten.
+	b := NewBitlist(10)
+	all := NewBitvector8()
+	all[0] = 0xFF
+	if err := WriteBits(b, 2, all); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{2, 3, 4, 5, 6, 7, 8, 9}; b.Len() != 10 || !reflect.DeepEqual(b.BitIndices(), want) {
+		t.Errorf("WriteBits() = %v (len %d), wanted %v", b.BitIndices(), b.Len(), want)
+	}
+}
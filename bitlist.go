@@ -137,7 +137,9 @@ func (b Bitlist) BytesNoTrim() []byte {
 
 // ToBitlist64 converts []byte backed bitlist into []uint64 backed bitlist.
 func (b Bitlist) ToBitlist64() (*Bitlist64, error) {
-	return NewBitlist64FromBytes(b.Len(), b.BytesNoTrim())
+	ret := NewBitlist64(0)
+	ret.CopyFromBitlist(b)
+	return ret, nil
 }
 
 // Count returns the number of 1s in the bitlist.
@@ -159,7 +161,7 @@ func (b Bitlist) Count() uint64 {
 // bitlist. This method will return an error if bitlists are not the same length.
 func (b Bitlist) Contains(c Bitlist) (bool, error) {
 	if b.Len() != c.Len() {
-		return false, ErrBitlistDifferentLength
+		return false, lengthMismatch("Bitlist.Contains", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	for i := 0; i < len(b); i++ {
@@ -176,7 +178,7 @@ func (b Bitlist) Contains(c Bitlist) (bool, error) {
 func (b Bitlist) Overlaps(c Bitlist) (bool, error) {
 	lenB, lenC := b.Len(), c.Len()
 	if lenB != lenC {
-		return false, ErrBitlistDifferentLength
+		return false, lengthMismatch("Bitlist.Overlaps", ErrBitlistDifferentLength, lenB, lenC)
 	}
 
 	if lenB == 0 || lenC == 0 {
@@ -206,7 +208,7 @@ func (b Bitlist) Overlaps(c Bitlist) (bool, error) {
 // Or returns the OR result of the two bitfields. This method will return an error if the bitlists are not the same length.
 func (b Bitlist) Or(c Bitlist) (Bitlist, error) {
 	if b.Len() != c.Len() {
-		return nil, ErrBitlistDifferentLength
+		return nil, lengthMismatch("Bitlist.Or", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	ret := make([]byte, len(b))
@@ -221,8 +223,11 @@ func (b Bitlist) Or(c Bitlist) (Bitlist, error) {
 // Result is written into provided variable, so no allocation takes place inside the function.
 // This method will return an error if the bitlists are not the same length.
 func (b Bitlist) NoAllocOr(c, ret Bitlist) error {
-	if b.Len() != c.Len() || b.Len() != ret.Len() {
-		return ErrBitlistDifferentLength
+	if b.Len() != c.Len() {
+		return lengthMismatch("Bitlist.NoAllocOr", ErrBitlistDifferentLength, b.Len(), c.Len())
+	}
+	if b.Len() != ret.Len() {
+		return lengthMismatch("Bitlist.NoAllocOr", ErrBitlistDifferentLength, b.Len(), ret.Len())
 	}
 
 	for idx, word := range b {
@@ -234,7 +239,7 @@ func (b Bitlist) NoAllocOr(c, ret Bitlist) error {
 // And returns the AND result of the two bitfields. This method will return an error if the bitlists are not the same length.
 func (b Bitlist) And(c Bitlist) (Bitlist, error) {
 	if b.Len() != c.Len() {
-		return nil, ErrBitlistDifferentLength
+		return nil, lengthMismatch("Bitlist.And", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	ret := make([]byte, len(b))
@@ -248,7 +253,7 @@ func (b Bitlist) And(c Bitlist) (Bitlist, error) {
 // Xor returns the XOR result of the two bitfields. This method will return an error if the bitlists are not the same length.
 func (b Bitlist) Xor(c Bitlist) (Bitlist, error) {
 	if b.Len() != c.Len() {
-		return nil, ErrBitlistDifferentLength
+		return nil, lengthMismatch("Bitlist.Xor", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	// Process all bytes but the last.
@@ -312,3 +317,110 @@ func (b Bitlist) BitIndices() []int {
 
 	return indices
 }
+
+// Append returns a copy of the bitlist with a single bit added to the end, moving the length bit
+// one position up. The receiver is left unchanged.
+func (b Bitlist) Append(bit bool) Bitlist {
+	b = b.Grow(1)
+	if bit {
+		b.SetBitAt(b.Len()-1, true)
+	}
+
+	return b
+}
+
+// Grow returns a copy of the bitlist extended by n zero bits, with the length bit moved to its new
+// position. The receiver is left unchanged, even when it has spare capacity.
+func (b Bitlist) Grow(n uint64) Bitlist {
+	size := b.Len()
+	newSize := size + n
+
+	ret := make(Bitlist, newSize/8+1)
+	copy(ret, b)
+	// Clear the old length bit, it becomes a regular (zero) bit.
+	ret[size/8] &^= uint8(1 << (size % 8))
+	ret[newSize/8] |= uint8(1 << (newSize % 8))
+
+	return ret
+}
+
+// Truncate returns a copy of the bitlist shortened to its first n bits. If n is not smaller than
+// the length of the bitlist, the copy holds all the bits. The receiver is left unchanged.
+func (b Bitlist) Truncate(n uint64) Bitlist {
+	if size := b.Len(); n > size {
+		n = size
+	}
+
+	ret := make(Bitlist, n/8+1)
+	copy(ret, b)
+	ret.setLengthBit(n)
+
+	return ret
+}
+
+// Concat returns a copy of the bitlist with the bits of all the provided bitlists appended to
+// the end. The receiver is left unchanged.
+func (b Bitlist) Concat(others ...Bitlist) Bitlist {
+	// Lengths are captured beforehand, as the receiver itself may be passed as an argument.
+	lens := make([]uint64, len(others))
+	total := uint64(0)
+	for i, o := range others {
+		lens[i] = o.Len()
+		total += lens[i]
+	}
+
+	offset := b.Len()
+	b = b.Grow(total)
+	for i, o := range others {
+		copyBits(b, offset, o, 0, lens[i])
+		offset += lens[i]
+	}
+
+	return b
+}
+
+// Slice returns a new bitlist holding the bits in the [start, end) range of the bitlist.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b Bitlist) Slice(start, end uint64) (Bitlist, error) {
+	if err := checkRange("Bitlist.Slice", start, end, b.Len()); err != nil {
+		return nil, err
+	}
+
+	ret := NewBitlist(end - start)
+	copyBits(ret, 0, b, start, end-start)
+
+	return ret, nil
+}
+
+// copyBits copies n bits from src (starting at srcOffset) into dst (starting at dstOffset).
+// Bits of dst outside of the target range are left untouched.
+func copyBits(dst []byte, dstOffset uint64, src []byte, srcOffset, n uint64) {
+	for n > 0 {
+		// Copy as many bits as fit into the current destination byte.
+		chunk := 8 - dstOffset%8
+		if chunk > n {
+			chunk = n
+		}
+
+		// Read 8 bits starting at the (possibly unaligned) source offset.
+		i, shift := srcOffset/8, srcOffset%8
+		v := src[i] >> shift
+		if shift != 0 && i+1 < uint64(len(src)) {
+			v |= src[i+1] << (8 - shift)
+		}
+
+		mask := uint8(0xff>>(8-chunk)) << (dstOffset % 8)
+		dst[dstOffset/8] = dst[dstOffset/8]&^mask | (v<<(dstOffset%8))&mask
+
+		dstOffset += chunk
+		srcOffset += chunk
+		n -= chunk
+	}
+}
+
+// setLengthBit puts the length bit at position n, clearing any bits above it in the last byte.
+// The bitlist is expected to be exactly n/8+1 bytes long.
+func (b Bitlist) setLengthBit(n uint64) {
+	b[n/8] &= uint8(1<<(n%8)) - 1
+	b[n/8] |= uint8(1 << (n % 8))
+}
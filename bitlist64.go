@@ -2,8 +2,8 @@ package bitfield
 
 import (
 	"encoding/binary"
-	"fmt"
 	"math/bits"
+	"slices"
 )
 
 var _ = Bitfield(&Bitlist64{})
@@ -49,7 +49,7 @@ func NewBitlist64From(data []uint64) *Bitlist64 {
 // perfectly to the word size).
 func NewBitlist64FromBytes(n uint64, b []byte) (*Bitlist64, error) {
 	if n > uint64(len(b)<<3) {
-		return nil, fmt.Errorf("an array of %d bytes is not enough to hold n=%d bits", len(b), n)
+		return nil, lengthMismatch("NewBitlist64FromBytes", ErrBitlistWrongLen, uint64(len(b)), (n+7)/8)
 	}
 	// Extend input slice with zero bytes if it isn't evenly divisible by word size.
 	if numExtraBytes := len(b) % bytesInWord; numExtraBytes != 0 {
@@ -136,30 +136,57 @@ func (b *Bitlist64) Bytes() []byte {
 
 // ToBitlist converts []uint64 backed bitlist into []byte backed bitlist.
 func (b *Bitlist64) ToBitlist() Bitlist {
-	if len(b.data) == 0 || b.size == 0 {
-		return NewBitlist(0)
+	return b.AppendBitlist(make([]byte, 0, b.size>>3+1))
+}
+
+// AppendBitlist appends the []byte backed bitlist representation of the bitlist, including the
+// length bit, to dst and returns the extended slice. It allocates only if dst lacks the capacity,
+// so a buffer can be reused across calls.
+func (b *Bitlist64) AppendBitlist(dst []byte) []byte {
+	// Bytes fully covered by the bits, the remaining bits share the last byte with the length bit.
+	fullBytes := b.size >> 3
+	dst = slices.Grow(dst, int(fullBytes)+1)
+	i := uint64(0)
+	for ; i+bytesInWord <= fullBytes; i += bytesInWord {
+		dst = binary.LittleEndian.AppendUint64(dst, b.data[i>>bytesInWordLog2])
+	}
+	for ; i < fullBytes; i++ {
+		dst = append(dst, b.byteAt(i))
 	}
 
-	ret := make([]byte, len(b.data)*bytesInWord)
-	for idx, word := range b.data {
-		start := idx << bytesInWordLog2
-		binary.LittleEndian.PutUint64(ret[start:start+bytesInWord], word)
+	// Set size bit. If number of bits align evenly with number byte size (8), it takes an extra byte.
+	last := uint8(1 << (b.size % 8))
+	if b.size%8 != 0 {
+		last |= b.byteAt(fullBytes)
 	}
+	return append(dst, last)
+}
 
-	// Append size bit. If number of bits align evenly with number byte size (8), add extra byte.
-	// Otherwise, set the most significant bit as a length bit.
-	if b.size%8 == 0 {
-		// Limit number of bits to a known size. Add extra byte, with size bit set.
-		ret = append(ret[:b.size>>3], 0x1)
+// CopyFromBitlist replaces the bitlist with the bits of a []byte backed bitlist, resizing it to
+// the length of src. The underlying array is reused if it has enough capacity.
+func (b *Bitlist64) CopyFromBitlist(src Bitlist) {
+	size := src.Len()
+	if need := numWordsRequired(size); cap(b.data) >= need {
+		b.data = b.data[:need]
 	} else {
-		// Limit number of bits to a known size.
-		ret = ret[:b.size>>3+1]
-		// Set size bit on the last byte.
-		idx := uint8(1 << (b.size % 8))
-		ret[len(ret)-1] |= idx
+		b.data = make([]uint64, need)
 	}
+	b.size = size
 
-	return ret
+	data := src[:(size+7)>>3]
+	i := 0
+	for ; i+bytesInWord <= len(data); i += bytesInWord {
+		b.data[i>>bytesInWordLog2] = binary.LittleEndian.Uint64(data[i:])
+	}
+	if i < len(data) {
+		var word [bytesInWord]byte
+		copy(word[:], data[i:])
+		b.data[i>>bytesInWordLog2] = binary.LittleEndian.Uint64(word[:])
+	}
+	// The last byte may hold the length bit.
+	if size != 0 {
+		b.clearUnusedBits()
+	}
 }
 
 // Count returns the number of 1s in the bitlist.
@@ -177,7 +204,7 @@ func (b *Bitlist64) Count() uint64 {
 // This method will return an error if bitlists are not the same length.
 func (b *Bitlist64) Contains(c *Bitlist64) (bool, error) {
 	if b.Len() != c.Len() {
-		return false, ErrBitlistDifferentLength
+		return false, lengthMismatch("Bitlist64.Contains", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	// To ensure all of the bits in c are present in b, we iterate over every word, combine
@@ -197,7 +224,7 @@ func (b *Bitlist64) Contains(c *Bitlist64) (bool, error) {
 func (b *Bitlist64) Overlaps(c *Bitlist64) (bool, error) {
 	lenB, lenC := b.Len(), c.Len()
 	if lenB != lenC {
-		return false, ErrBitlistDifferentLength
+		return false, lengthMismatch("Bitlist64.Overlaps", ErrBitlistDifferentLength, lenB, lenC)
 	}
 
 	if lenB == 0 || lenC == 0 {
@@ -220,7 +247,7 @@ func (b *Bitlist64) Overlaps(c *Bitlist64) (bool, error) {
 // This method will return an error if the bitlists are not the same length.
 func (b *Bitlist64) Or(c *Bitlist64) (*Bitlist64, error) {
 	if b.Len() != c.Len() {
-		return nil, ErrBitlistDifferentLength
+		return nil, lengthMismatch("Bitlist64.Or", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	ret := b.Clone()
@@ -233,8 +260,11 @@ func (b *Bitlist64) Or(c *Bitlist64) (*Bitlist64, error) {
 // Result is written into provided variable, so no allocation takes place inside the function.
 // This method will return an error if the bitlists are not the same length.
 func (b *Bitlist64) NoAllocOr(c, ret *Bitlist64) error {
-	if b.Len() != c.Len() || b.Len() != ret.Len() {
-		return ErrBitlistDifferentLength
+	if b.Len() != c.Len() {
+		return lengthMismatch("Bitlist64.NoAllocOr", ErrBitlistDifferentLength, b.Len(), c.Len())
+	}
+	if b.Len() != ret.Len() {
+		return lengthMismatch("Bitlist64.NoAllocOr", ErrBitlistDifferentLength, b.Len(), ret.Len())
 	}
 
 	for idx, word := range b.data {
@@ -247,7 +277,7 @@ func (b *Bitlist64) NoAllocOr(c, ret *Bitlist64) error {
 // This method will return an error if the bitlists are not the same length.
 func (b *Bitlist64) OrCount(c *Bitlist64) (uint64, error) {
 	if b.Len() != c.Len() {
-		return 0, ErrBitlistDifferentLength
+		return 0, lengthMismatch("Bitlist64.OrCount", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	var cnt int
@@ -262,7 +292,7 @@ func (b *Bitlist64) OrCount(c *Bitlist64) (uint64, error) {
 // This method will return an error if the bitlists are not the same length.
 func (b *Bitlist64) And(c *Bitlist64) (*Bitlist64, error) {
 	if b.Len() != c.Len() {
-		return nil, ErrBitlistDifferentLength
+		return nil, lengthMismatch("Bitlist64.And", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	ret := b.Clone()
@@ -275,7 +305,7 @@ func (b *Bitlist64) And(c *Bitlist64) (*Bitlist64, error) {
 // This method will return an error if the bitlists are not the same length.
 func (b *Bitlist64) AndCount(c *Bitlist64) (uint64, error) {
 	if b.Len() != c.Len() {
-		return 0, ErrBitlistDifferentLength
+		return 0, lengthMismatch("Bitlist64.AndCount", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	var cnt int
@@ -290,8 +320,11 @@ func (b *Bitlist64) AndCount(c *Bitlist64) (uint64, error) {
 // Result is written into provided variable, so no allocation takes place inside the function.
 // This method will return an error if the bitlists are not the same length.
 func (b *Bitlist64) NoAllocAnd(c, ret *Bitlist64) error {
-	if b.Len() != c.Len() || b.Len() != ret.Len() {
-		return ErrBitlistDifferentLength
+	if b.Len() != c.Len() {
+		return lengthMismatch("Bitlist64.NoAllocAnd", ErrBitlistDifferentLength, b.Len(), c.Len())
+	}
+	if b.Len() != ret.Len() {
+		return lengthMismatch("Bitlist64.NoAllocAnd", ErrBitlistDifferentLength, b.Len(), ret.Len())
 	}
 
 	for idx, word := range b.data {
@@ -304,7 +337,7 @@ func (b *Bitlist64) NoAllocAnd(c, ret *Bitlist64) error {
 // This method will return an error if the bitlists are not the same length.
 func (b *Bitlist64) Xor(c *Bitlist64) (*Bitlist64, error) {
 	if b.Len() != c.Len() {
-		return nil, ErrBitlistDifferentLength
+		return nil, lengthMismatch("Bitlist64.Xor", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	ret := b.Clone()
@@ -317,8 +350,11 @@ func (b *Bitlist64) Xor(c *Bitlist64) (*Bitlist64, error) {
 // Result is written into provided variable, so no allocation takes place inside the function.
 // This method will return an error if the bitlists are not the same length.
 func (b *Bitlist64) NoAllocXor(c, ret *Bitlist64) error {
-	if b.Len() != c.Len() || b.Len() != ret.Len() {
-		return ErrBitlistDifferentLength
+	if b.Len() != c.Len() {
+		return lengthMismatch("Bitlist64.NoAllocXor", ErrBitlistDifferentLength, b.Len(), c.Len())
+	}
+	if b.Len() != ret.Len() {
+		return lengthMismatch("Bitlist64.NoAllocXor", ErrBitlistDifferentLength, b.Len(), ret.Len())
 	}
 
 	for idx, word := range b.data {
@@ -331,7 +367,7 @@ func (b *Bitlist64) NoAllocXor(c, ret *Bitlist64) error {
 // This method will return an error if the bitlists are not the same length.
 func (b *Bitlist64) XorCount(c *Bitlist64) (uint64, error) {
 	if b.Len() != c.Len() {
-		return 0, ErrBitlistDifferentLength
+		return 0, lengthMismatch("Bitlist64.XorCount", ErrBitlistDifferentLength, b.Len(), c.Len())
 	}
 
 	var cnt int
@@ -420,6 +456,68 @@ func (b *Bitlist64) Clone() *Bitlist64 {
 	return c
 }
 
+// Append adds a single bit to the end of the bitlist.
+// The underlying array grows in amortized constant time, similar to the builtin append.
+func (b *Bitlist64) Append(bit bool) {
+	b.Grow(1)
+	if bit {
+		b.data[(b.size-1)>>wordSizeLog2] |= uint64(1 << ((b.size - 1) % wordSize))
+	}
+}
+
+// Grow extends the bitlist by n zero bits.
+func (b *Bitlist64) Grow(n uint64) {
+	b.size += n
+	if need := numWordsRequired(b.size); need > len(b.data) {
+		// Appended words are explicitly zeroed, so stale data left in the spare capacity by an
+		// earlier Truncate never resurfaces.
+		b.data = append(b.data, make([]uint64, need-len(b.data))...)
+	}
+}
+
+// Truncate shortens the bitlist to its first n bits. If n is not smaller than the length of
+// the bitlist, this method does nothing.
+func (b *Bitlist64) Truncate(n uint64) {
+	if n >= b.size {
+		return
+	}
+
+	b.size = n
+	b.data = b.data[:numWordsRequired(n)]
+	b.clearUnusedBits()
+}
+
+// Concat appends the bits of all the provided bitlists to the end of the bitlist.
+func (b *Bitlist64) Concat(others ...*Bitlist64) {
+	// Lengths are captured beforehand, as the receiver itself may be passed as an argument.
+	lens := make([]uint64, len(others))
+	total := uint64(0)
+	for i, o := range others {
+		lens[i] = o.Len()
+		total += lens[i]
+	}
+
+	offset := b.size
+	b.Grow(total)
+	for i, o := range others {
+		copyWordBits(b.data, offset, o.data, 0, lens[i])
+		offset += lens[i]
+	}
+}
+
+// Slice returns a new bitlist holding the bits in the [start, end) range of the bitlist.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b *Bitlist64) Slice(start, end uint64) (*Bitlist64, error) {
+	if err := checkRange("Bitlist64.Slice", start, end, b.size); err != nil {
+		return nil, err
+	}
+
+	ret := NewBitlist64(end - start)
+	copyWordBits(ret.data, 0, b.data, start, end-start)
+
+	return ret, nil
+}
+
 // numWordsRequired calculates how many words are required to hold bitlist of n bits.
 func numWordsRequired(n uint64) int {
 	return int((n + (wordSize - 1)) >> wordSizeLog2)
@@ -432,3 +530,29 @@ func (b *Bitlist64) clearUnusedBits() {
 		b.data[len(b.data)-1] &= allBitsSet >> (wordSize - b.size%wordSize)
 	}
 }
+
+// copyWordBits copies n bits from src (starting at srcOffset) into dst (starting at dstOffset).
+// Bits of dst outside of the target range are left untouched.
+func copyWordBits(dst []uint64, dstOffset uint64, src []uint64, srcOffset, n uint64) {
+	for n > 0 {
+		// Copy as many bits as fit into the current destination word.
+		chunk := wordSize - dstOffset%wordSize
+		if chunk > n {
+			chunk = n
+		}
+
+		// Read a full word starting at the (possibly unaligned) source offset.
+		i, shift := srcOffset>>wordSizeLog2, srcOffset%wordSize
+		v := src[i] >> shift
+		if shift != 0 && i+1 < uint64(len(src)) {
+			v |= src[i+1] << (wordSize - shift)
+		}
+
+		mask := (allBitsSet >> (wordSize - chunk)) << (dstOffset % wordSize)
+		dst[dstOffset>>wordSizeLog2] = dst[dstOffset>>wordSizeLog2]&^mask | (v<<(dstOffset%wordSize))&mask
+
+		dstOffset += chunk
+		srcOffset += chunk
+		n -= chunk
+	}
+}
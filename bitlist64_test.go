@@ -2,6 +2,7 @@ package bitfield
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -400,6 +401,58 @@ func TestBitlist64_ToBitlist(t *testing.T) {
 	}
 }
 
+func TestBitlist64_AppendBitlist(t *testing.T) {
+	prefix := []byte{0xAA, 0xBB}
+	for _, size := range []uint64{0, 1, 7, 8, 9, 63, 64, 65, 71, 72, 200} {
+		b := NewBitlist64(size)
+		for i := uint64(0); i < size; i += 5 {
+			b.SetBitAt(i, true)
+		}
+		wanted := NewBitlist(size)
+		for _, idx := range b.BitIndices() {
+			wanted.SetBitAt(uint64(idx), true)
+		}
+
+		buf := make([]byte, len(prefix), 64)
+		copy(buf, prefix)
+		got := b.AppendBitlist(buf)
+		if !bytes.Equal(got[:len(prefix)], prefix) || !bytes.Equal(got[len(prefix):], wanted) {
+			t.Errorf("size %d: AppendBitlist() = %#x, wanted %#x after the prefix", size, got, wanted)
+		}
+		if &got[0] != &buf[0] {
+			t.Errorf("size %d: AppendBitlist() reallocated a buffer with enough capacity", size)
+		}
+	}
+}
+
+func TestBitlist64_CopyFromBitlist(t *testing.T) {
+	b := NewBitlist64From([]uint64{allBitsSet, allBitsSet, allBitsSet})
+	data := b.data
+	for _, size := range []uint64{130, 64, 9, 0, 65, 192} {
+		src := NewBitlist(size)
+		for i := uint64(0); i < size; i += 3 {
+			src.SetBitAt(i, true)
+		}
+		b.CopyFromBitlist(src)
+
+		wanted, err := NewBitlist64FromBytes(size, src.BytesNoTrim())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !b.Equal(*wanted) || !reflect.DeepEqual(b.data, wanted.data) {
+			t.Errorf("size %d: CopyFromBitlist() = %+v, wanted %+v", size, b, wanted)
+		}
+		if len(b.data) > 0 && &b.data[0] != &data[0] {
+			t.Errorf("size %d: CopyFromBitlist() reallocated an array with enough capacity", size)
+		}
+	}
+
+	b.CopyFromBitlist(NewBitlist(1000))
+	if b.Len() != 1000 || b.Count() != 0 {
+		t.Errorf("CopyFromBitlist() into a smaller bitlist = %+v", b)
+	}
+}
+
 func TestBitlist64_Len(t *testing.T) {
 	tests := []struct {
 		bitlist *Bitlist64
@@ -1003,7 +1056,7 @@ func TestBitlist64_Overlaps(t *testing.T) {
 	t.Run("check errors", func(t *testing.T) {
 		a := NewBitlist64(64)
 		b := NewBitlist64(128)
-		if _, err := a.Overlaps(b); err != ErrBitlistDifferentLength {
+		if _, err := a.Overlaps(b); !errors.Is(err, ErrBitlistDifferentLength) {
 			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 		}
 	})
@@ -1093,7 +1146,7 @@ func TestBitlist64_Or(t *testing.T) {
 		t.Run("Or()", func(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(128)
-			if _, err := a.Or(b); err != ErrBitlistDifferentLength {
+			if _, err := a.Or(b); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
@@ -1101,7 +1154,7 @@ func TestBitlist64_Or(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(128)
 			ret := NewBitlist64(64)
-			if err := a.NoAllocOr(b, ret); err != ErrBitlistDifferentLength {
+			if err := a.NoAllocOr(b, ret); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
@@ -1109,14 +1162,14 @@ func TestBitlist64_Or(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(64)
 			ret := NewBitlist64(128)
-			if err := a.NoAllocOr(b, ret); err != ErrBitlistDifferentLength {
+			if err := a.NoAllocOr(b, ret); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
 		t.Run("OrCount()", func(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(128)
-			if _, err := a.OrCount(b); err != ErrBitlistDifferentLength {
+			if _, err := a.OrCount(b); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
@@ -1208,7 +1261,7 @@ func TestBitlist64_And(t *testing.T) {
 
 			a := NewBitlist64(64)
 			b := NewBitlist64(128)
-			if _, err := a.And(b); err != ErrBitlistDifferentLength {
+			if _, err := a.And(b); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
@@ -1216,7 +1269,7 @@ func TestBitlist64_And(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(128)
 			ret := NewBitlist64(64)
-			if err := a.NoAllocAnd(b, ret); err != ErrBitlistDifferentLength {
+			if err := a.NoAllocAnd(b, ret); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
@@ -1224,14 +1277,14 @@ func TestBitlist64_And(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(64)
 			ret := NewBitlist64(128)
-			if err := a.NoAllocAnd(b, ret); err != ErrBitlistDifferentLength {
+			if err := a.NoAllocAnd(b, ret); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
 		t.Run("AndCount()", func(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(128)
-			if _, err := a.AndCount(b); err != ErrBitlistDifferentLength {
+			if _, err := a.AndCount(b); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
@@ -1327,7 +1380,7 @@ func TestBitlist64_Xor(t *testing.T) {
 		t.Run("Xor()", func(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(128)
-			if _, err := a.Xor(b); err != ErrBitlistDifferentLength {
+			if _, err := a.Xor(b); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
@@ -1335,7 +1388,7 @@ func TestBitlist64_Xor(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(128)
 			ret := NewBitlist64(64)
-			if err := a.NoAllocXor(b, ret); err != ErrBitlistDifferentLength {
+			if err := a.NoAllocXor(b, ret); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
@@ -1343,14 +1396,14 @@ func TestBitlist64_Xor(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(64)
 			ret := NewBitlist64(128)
-			if err := a.NoAllocXor(b, ret); err != ErrBitlistDifferentLength {
+			if err := a.NoAllocXor(b, ret); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
 		t.Run("XorCount()", func(t *testing.T) {
 			a := NewBitlist64(64)
 			b := NewBitlist64(128)
-			if _, err := a.XorCount(b); err != ErrBitlistDifferentLength {
+			if _, err := a.XorCount(b); !errors.Is(err, ErrBitlistDifferentLength) {
 				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
 			}
 		})
@@ -1500,3 +1553,215 @@ func TestBitlist64_BitIndices(t *testing.T) {
 		}
 	}
 }
+
+func TestBitlist64_Append(t *testing.T) {
+	b := NewBitlist64(0)
+	want := NewBitlist64(200)
+	for i := uint64(0); i < 200; i++ {
+		b.Append(i%3 == 0)
+		want.SetBitAt(i, i%3 == 0)
+		if b.Len() != i+1 {
+			t.Fatalf("Len() = %d, wanted %d", b.Len(), i+1)
+		}
+	}
+	if !reflect.DeepEqual(b.data, want.data) {
+		t.Errorf("Append() = %#x, wanted %#x", b.data, want.data)
+	}
+}
+
+func TestBitlist64_Grow(t *testing.T) {
+	tests := []struct {
+		bitlist *Bitlist64
+		n       uint64
+		want    *Bitlist64
+	}{
+		{
+			bitlist: NewBitlist64(0),
+			n:       3,
+			want:    &Bitlist64{size: 3, data: []uint64{0x00}},
+		},
+		{
+			bitlist: &Bitlist64{size: 3, data: []uint64{0x05}},
+			n:       0,
+			want:    &Bitlist64{size: 3, data: []uint64{0x05}},
+		},
+		{
+			bitlist: &Bitlist64{size: 3, data: []uint64{0x05}},
+			n:       61,
+			want:    &Bitlist64{size: 64, data: []uint64{0x05}},
+		},
+		{
+			bitlist: &Bitlist64{size: 3, data: []uint64{0x05}},
+			n:       62,
+			want:    &Bitlist64{size: 65, data: []uint64{0x05, 0x00}},
+		},
+		{
+			bitlist: NewBitlist64From([]uint64{0xFF}),
+			n:       200,
+			want:    &Bitlist64{size: 264, data: []uint64{0xFF, 0x00, 0x00, 0x00, 0x00}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.bitlist.Grow(tt.n)
+		if !reflect.DeepEqual(tt.bitlist, tt.want) {
+			t.Errorf("Grow(%d) = %+v, wanted %+v", tt.n, tt.bitlist, tt.want)
+		}
+	}
+
+	t.Run("stale capacity", func(t *testing.T) {
+		b := NewBitlist64From([]uint64{allBitsSet, allBitsSet, allBitsSet})
+		b.Truncate(4)
+		b.Grow(160)
+		want := &Bitlist64{size: 164, data: []uint64{0x0F, 0x00, 0x00}}
+		if !reflect.DeepEqual(b, want) {
+			t.Errorf("Truncate(4).Grow(160) = %+v, wanted %+v", b, want)
+		}
+	})
+}
+
+func TestBitlist64_Truncate(t *testing.T) {
+	tests := []struct {
+		bitlist *Bitlist64
+		n       uint64
+		want    *Bitlist64
+	}{
+		{
+			bitlist: NewBitlist64From([]uint64{0xFF}),
+			n:       64,
+			want:    &Bitlist64{size: 64, data: []uint64{0xFF}},
+		},
+		{
+			bitlist: NewBitlist64From([]uint64{0xFF}),
+			n:       100,
+			want:    &Bitlist64{size: 64, data: []uint64{0xFF}},
+		},
+		{
+			bitlist: NewBitlist64From([]uint64{0xFF}),
+			n:       3,
+			want:    &Bitlist64{size: 3, data: []uint64{0x07}},
+		},
+		{
+			bitlist: NewBitlist64From([]uint64{0xFF}),
+			n:       0,
+			want:    &Bitlist64{size: 0, data: []uint64{}},
+		},
+		{
+			bitlist: NewBitlist64From([]uint64{allBitsSet, allBitsSet}),
+			n:       65,
+			want:    &Bitlist64{size: 65, data: []uint64{allBitsSet, 0x01}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.bitlist.Truncate(tt.n)
+		if !reflect.DeepEqual(tt.bitlist, tt.want) {
+			t.Errorf("Truncate(%d) = %+v, wanted %+v", tt.n, tt.bitlist, tt.want)
+		}
+	}
+}
+
+func TestBitlist64_Concat(t *testing.T) {
+	tests := []struct {
+		bitlist *Bitlist64
+		others  []*Bitlist64
+		want    *Bitlist64
+	}{
+		{
+			bitlist: NewBitlist64(0),
+			others:  nil,
+			want:    &Bitlist64{size: 0, data: []uint64{}},
+		},
+		{
+			bitlist: &Bitlist64{size: 3, data: []uint64{0x05}},
+			others: []*Bitlist64{
+				{size: 3, data: []uint64{0x06}},
+				{size: 4, data: []uint64{0x0F}},
+			},
+			want: &Bitlist64{size: 10, data: []uint64{0x3F5}},
+		},
+		{
+			bitlist: &Bitlist64{size: 60, data: []uint64{0x01}},
+			others: []*Bitlist64{
+				NewBitlist64From([]uint64{0xF00000000000000F}),
+			},
+			want: &Bitlist64{size: 124, data: []uint64{0xF000000000000001, 0x0F00000000000000}},
+		},
+		{
+			bitlist: NewBitlist64From([]uint64{0x01}),
+			others: []*Bitlist64{
+				NewBitlist64From([]uint64{0x02}),
+				{size: 1, data: []uint64{0x01}},
+			},
+			want: &Bitlist64{size: 129, data: []uint64{0x01, 0x02, 0x01}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.bitlist.Concat(tt.others...)
+		if !reflect.DeepEqual(tt.bitlist, tt.want) {
+			t.Errorf("Concat() = %+v, wanted %+v", tt.bitlist, tt.want)
+		}
+	}
+
+	t.Run("self", func(t *testing.T) {
+		b := &Bitlist64{size: 40, data: []uint64{0x8000000001}}
+		b.Concat(b)
+		want := &Bitlist64{size: 80, data: []uint64{0x18000000001, 0x8000}}
+		if !reflect.DeepEqual(b, want) {
+			t.Errorf("Concat(self) = %+v, wanted %+v", b, want)
+		}
+	})
+}
+
+func TestBitlist64_Slice(t *testing.T) {
+	src := NewBitlist64From([]uint64{0xF00000000000000F, 0xAAAAAAAAAAAAAAAA})
+	tests := []struct {
+		start uint64
+		end   uint64
+		want  *Bitlist64
+	}{
+		{
+			start: 0,
+			end:   0,
+			want:  &Bitlist64{size: 0, data: []uint64{}},
+		},
+		{
+			start: 0,
+			end:   128,
+			want:  src.Clone(),
+		},
+		{
+			start: 2,
+			end:   6,
+			want:  &Bitlist64{size: 4, data: []uint64{0x03}},
+		},
+		{
+			start: 60,
+			end:   68,
+			want:  &Bitlist64{size: 8, data: []uint64{0xAF}},
+		},
+		{
+			start: 1,
+			end:   128,
+			want: &Bitlist64{size: 127, data: []uint64{
+				0x7800000000000007, 0x5555555555555555,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		if got, err := src.Slice(tt.start, tt.end); !reflect.DeepEqual(got, tt.want) || err != nil {
+			t.Errorf("Slice(%d, %d) = %+v, %v, wanted %+v", tt.start, tt.end, got, err, tt.want)
+		}
+	}
+
+	t.Run("check errors", func(t *testing.T) {
+		if _, err := src.Slice(5, 129); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+		}
+		if _, err := src.Slice(6, 5); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+		}
+	})
+}
@@ -821,3 +821,54 @@ func BenchmarkBitlist_BitIndices(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkBitlist_Convert(b *testing.B) {
+	for n := uint64(0); n <= 2048; n += 512 {
+		b.Run(fmt.Sprintf("size:%d", n), func(b *testing.B) {
+			b.Run("ToBitlist", func(b *testing.B) {
+				b.StopTimer()
+				s := NewBitlist64(n)
+				b.StartTimer()
+				for i := 0; i < b.N; i++ {
+					s.ToBitlist()
+				}
+			})
+			b.Run("AppendBitlist", func(b *testing.B) {
+				b.StopTimer()
+				s := NewBitlist64(n)
+				buf := make([]byte, 0, n/8+1)
+				b.StartTimer()
+				for i := 0; i < b.N; i++ {
+					buf = s.AppendBitlist(buf[:0])
+				}
+			})
+			b.Run("ToBitlist64", func(b *testing.B) {
+				b.StopTimer()
+				s := NewBitlist(n)
+				b.StartTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := s.ToBitlist64(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("CopyFromBitlist", func(b *testing.B) {
+				b.StopTimer()
+				s := NewBitlist(n)
+				dst := NewBitlist64(n)
+				b.StartTimer()
+				for i := 0; i < b.N; i++ {
+					dst.CopyFromBitlist(s)
+				}
+			})
+			b.Run("Bitlist64View", func(b *testing.B) {
+				b.StopTimer()
+				s := NewBitlist(n)
+				b.StartTimer()
+				for i := 0; i < b.N; i++ {
+					s.Bitlist64View()
+				}
+			})
+		})
+	}
+}
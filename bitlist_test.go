@@ -2,6 +2,7 @@ package bitfield
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -1024,3 +1025,320 @@ func TestBitlist_BitIndices(t *testing.T) {
 		}
 	}
 }
+
+func TestBitlist_Append(t *testing.T) {
+	tests := []struct {
+		bitlist Bitlist
+		bit     bool
+		want    Bitlist
+	}{
+		{
+			bitlist: Bitlist{},
+			bit:     true,
+			want:    Bitlist{0x03}, // 0b00000011
+		},
+		{
+			bitlist: Bitlist{0x01}, // 0b00000001
+			bit:     false,
+			want:    Bitlist{0x02}, // 0b00000010
+		},
+		{
+			bitlist: Bitlist{0x15}, // 0b00010101
+			bit:     true,
+			want:    Bitlist{0x35}, // 0b00110101
+		},
+		{
+			bitlist: Bitlist{0xFF}, // 0b11111111
+			bit:     true,
+			want:    Bitlist{0xFF, 0x01},
+		},
+		{
+			bitlist: Bitlist{0x80}, // 0b10000000
+			bit:     false,
+			want:    Bitlist{0x00, 0x01},
+		},
+		{
+			bitlist: Bitlist{0x00, 0x01},
+			bit:     true,
+			want:    Bitlist{0x00, 0x03},
+		},
+	}
+
+	for _, tt := range tests {
+		original := bytes.Clone(tt.bitlist)
+		if got := tt.bitlist.Append(tt.bit); !bytes.Equal(got, tt.want) {
+			t.Errorf("(%#x).Append(%t) = %#x, wanted %#x", original, tt.bit, got, tt.want)
+		}
+	}
+
+	t.Run("build bit by bit", func(t *testing.T) {
+		b := NewBitlist(0)
+		want := NewBitlist(100)
+		for i := uint64(0); i < 100; i++ {
+			b = b.Append(i%3 == 0)
+			want.SetBitAt(i, i%3 == 0)
+		}
+		if !bytes.Equal(b, want) {
+			t.Errorf("Append() = %#x, wanted %#x", b, want)
+		}
+	})
+}
+
+func TestBitlist_Grow(t *testing.T) {
+	tests := []struct {
+		bitlist Bitlist
+		n       uint64
+		want    Bitlist
+	}{
+		{
+			bitlist: Bitlist{},
+			n:       3,
+			want:    Bitlist{0x08},
+		},
+		{
+			bitlist: Bitlist{0x07}, // 0b00000111
+			n:       0,
+			want:    Bitlist{0x07},
+		},
+		{
+			bitlist: Bitlist{0x07}, // 0b00000111
+			n:       3,
+			want:    Bitlist{0x23}, // 0b00100011
+		},
+		{
+			bitlist: Bitlist{0x07}, // 0b00000111
+			n:       6,
+			want:    Bitlist{0x03, 0x01},
+		},
+		{
+			bitlist: Bitlist{0xFF, 0x01},
+			n:       17,
+			want:    Bitlist{0xFF, 0x00, 0x00, 0x02},
+		},
+	}
+
+	for _, tt := range tests {
+		original := bytes.Clone(tt.bitlist)
+		if got := tt.bitlist.Grow(tt.n); !bytes.Equal(got, tt.want) {
+			t.Errorf("(%#x).Grow(%d) = %#x, wanted %#x", original, tt.n, got, tt.want)
+		}
+	}
+
+	t.Run("stale capacity", func(t *testing.T) {
+		b := Bitlist{0xFF, 0xFF, 0x01}
+		b = b.Truncate(4).Grow(12)
+		want := NewBitlist(16)
+		for i := uint64(0); i < 4; i++ {
+			want.SetBitAt(i, true)
+		}
+		if !bytes.Equal(b, want) {
+			t.Errorf("Truncate(4).Grow(12) = %#x, wanted %#x", b, want)
+		}
+	})
+
+	t.Run("reallocation leaves the receiver intact", func(t *testing.T) {
+		a := Bitlist{0xC5} // 0b11000101, 7 bits.
+		c := a.Grow(1)
+		if !bytes.Equal(a, Bitlist{0xC5}) || a.Len() != 7 {
+			t.Errorf("Grow(1) modified the receiver to %#x", a)
+		}
+		if !bytes.Equal(c, Bitlist{0x45, 0x01}) {
+			t.Errorf("(0xc5).Grow(1) = %#x, wanted 0x4501", c)
+		}
+
+		// Append and Concat grow the receiver in the same way.
+		if c = a.Append(true); !bytes.Equal(a, Bitlist{0xC5}) || !bytes.Equal(c, Bitlist{0xC5, 0x01}) {
+			t.Errorf("Append(true) = %#x, receiver = %#x", c, a)
+		}
+		if c = a.Concat(a); !bytes.Equal(a, Bitlist{0xC5}) || c.Len() != 14 {
+			t.Errorf("Concat() = %#x, receiver = %#x", c, a)
+		}
+	})
+
+	t.Run("spare capacity leaves the receiver intact", func(t *testing.T) {
+		a := make(Bitlist, 1, 8)
+		a[0] = 0x0D // 0b00001101, 3 bits.
+		c := a.Grow(2)
+		if !bytes.Equal(a, Bitlist{0x0D}) || a.Len() != 3 {
+			t.Errorf("Grow(2) modified the receiver to %#x", a)
+		}
+		if !bytes.Equal(c, Bitlist{0x25}) {
+			t.Errorf("(0x0d).Grow(2) = %#x, wanted 0x25", c)
+		}
+		c.SetBitAt(4, true)
+		if !bytes.Equal(a, Bitlist{0x0D}) {
+			t.Errorf("SetBitAt() on the result modified the receiver to %#x", a)
+		}
+
+		if c = a.Append(false); !bytes.Equal(a, Bitlist{0x0D}) || !bytes.Equal(c, Bitlist{0x15}) {
+			t.Errorf("Append(false) = %#x, receiver = %#x", c, a)
+		}
+		if c = a.Concat(a); !bytes.Equal(a, Bitlist{0x0D}) || !bytes.Equal(c, Bitlist{0x6D}) {
+			t.Errorf("Concat() = %#x, receiver = %#x", c, a)
+		}
+
+		// Growing a truncated bitlist must not zero the bytes of the original.
+		b := Bitlist{0xFF, 0xFF, 0x01}
+		b.Truncate(4).Grow(12)
+		if !bytes.Equal(b, Bitlist{0xFF, 0xFF, 0x01}) {
+			t.Errorf("Truncate(4).Grow(12) modified the receiver to %#x", b)
+		}
+	})
+}
+
+func TestBitlist_Truncate(t *testing.T) {
+	tests := []struct {
+		bitlist Bitlist
+		n       uint64
+		want    Bitlist
+	}{
+		{
+			bitlist: Bitlist{0x1F}, // 0b00011111
+			n:       4,
+			want:    Bitlist{0x1F},
+		},
+		{
+			bitlist: Bitlist{0x1F}, // 0b00011111
+			n:       10,
+			want:    Bitlist{0x1F},
+		},
+		{
+			bitlist: Bitlist{0x1F}, // 0b00011111
+			n:       2,
+			want:    Bitlist{0x07}, // 0b00000111
+		},
+		{
+			bitlist: Bitlist{0x1F}, // 0b00011111
+			n:       0,
+			want:    Bitlist{0x01},
+		},
+		{
+			bitlist: Bitlist{0xFF, 0xFF, 0x01},
+			n:       8,
+			want:    Bitlist{0xFF, 0x01},
+		},
+		{
+			bitlist: Bitlist{0xFF, 0xFF, 0x01},
+			n:       9,
+			want:    Bitlist{0xFF, 0x03},
+		},
+	}
+
+	for _, tt := range tests {
+		original := bytes.Clone(tt.bitlist)
+		if got := tt.bitlist.Truncate(tt.n); !bytes.Equal(got, tt.want) {
+			t.Errorf("(%#x).Truncate(%d) = %#x, wanted %#x", original, tt.n, got, tt.want)
+		}
+		if !bytes.Equal(tt.bitlist, original) {
+			t.Errorf("(%#x).Truncate(%d) modified the receiver to %#x", original, tt.n, tt.bitlist)
+		}
+	}
+
+	t.Run("result does not share the receiver", func(t *testing.T) {
+		b := Bitlist{0xFF, 0xFF, 0x01}
+		c := b.Truncate(16)
+		c.SetBitAt(0, false)
+		if !bytes.Equal(b, Bitlist{0xFF, 0xFF, 0x01}) {
+			t.Errorf("SetBitAt() on the result modified the receiver to %#x", b)
+		}
+	})
+}
+
+func TestBitlist_Concat(t *testing.T) {
+	tests := []struct {
+		bitlist Bitlist
+		others  []Bitlist
+		want    Bitlist
+	}{
+		{
+			bitlist: Bitlist{0x01},
+			others:  nil,
+			want:    Bitlist{0x01},
+		},
+		{
+			bitlist: Bitlist{0x05},             // 0b00000101
+			others:  []Bitlist{{0x07}, {0x01}}, // 0b00000111, 0b00000001
+			want:    Bitlist{0x1D},             // 0b00011101
+		},
+		{
+			bitlist: Bitlist{0x0D},             // 0b00001101
+			others:  []Bitlist{{0x0E}, {0x1F}}, // 0b00001110, 0b00011111
+			want:    Bitlist{0xF5, 0x07},       // 0b11110101, 0b00000111
+		},
+		{
+			bitlist: Bitlist{0xFF, 0x01},
+			others:  []Bitlist{{0xAA, 0x02}, {0x02}},
+			want:    Bitlist{0xFF, 0xAA, 0x04},
+		},
+	}
+
+	for _, tt := range tests {
+		original := bytes.Clone(tt.bitlist)
+		if got := tt.bitlist.Concat(tt.others...); !bytes.Equal(got, tt.want) {
+			t.Errorf("(%#x).Concat(%#x) = %#x, wanted %#x", original, tt.others, got, tt.want)
+		}
+	}
+
+	t.Run("self", func(t *testing.T) {
+		b := Bitlist{0x0D} // 0b00001101
+		if got, want := b.Concat(b, b), (Bitlist{0x6D, 0x03}); !bytes.Equal(got, want) {
+			t.Errorf("Concat(self, self) = %#x, wanted %#x", got, want)
+		}
+	})
+}
+
+func TestBitlist_Slice(t *testing.T) {
+	tests := []struct {
+		bitlist Bitlist
+		start   uint64
+		end     uint64
+		want    Bitlist
+	}{
+		{
+			bitlist: Bitlist{0xE5, 0x07}, // 0b11100101, 0b00000111
+			start:   0,
+			end:     0,
+			want:    Bitlist{0x01},
+		},
+		{
+			bitlist: Bitlist{0xE5, 0x07},
+			start:   0,
+			end:     10,
+			want:    Bitlist{0xE5, 0x07},
+		},
+		{
+			bitlist: Bitlist{0xE5, 0x07},
+			start:   3,
+			end:     6,
+			want:    Bitlist{0x0C}, // 0b00001100
+		},
+		{
+			bitlist: Bitlist{0xE5, 0x07},
+			start:   6,
+			end:     10,
+			want:    Bitlist{0x1F}, // 0b00011111
+		},
+		{
+			bitlist: Bitlist{0xE5, 0x07},
+			start:   1,
+			end:     9,
+			want:    Bitlist{0xF2, 0x01},
+		},
+	}
+
+	for _, tt := range tests {
+		if got, err := tt.bitlist.Slice(tt.start, tt.end); !bytes.Equal(got, tt.want) || err != nil {
+			t.Errorf("(%#x).Slice(%d, %d) = %#x, %v, wanted %#x", tt.bitlist, tt.start, tt.end, got, err, tt.want)
+		}
+	}
+
+	t.Run("check errors", func(t *testing.T) {
+		b := NewBitlist(10)
+		if _, err := b.Slice(5, 11); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+		}
+		if _, err := b.Slice(6, 5); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+		}
+	})
+}
@@ -125,7 +125,7 @@ func (b Bitvector128) BitIndices() []int {
 // bitlist. This method will return an error if bitlists are not the same length.
 func (b Bitvector128) Contains(c Bitvector128) (bool, error) {
 	if b.Len() != c.Len() {
-		return false, ErrBitvectorDifferentLength
+		return false, lengthMismatch("Bitvector128.Contains", ErrBitvectorDifferentLength, b.Len(), c.Len())
 	}
 
 	// To ensure all of the bits in c are present in b, we iterate over every byte, combine
@@ -145,7 +145,7 @@ func (b Bitvector128) Contains(c Bitvector128) (bool, error) {
 func (b Bitvector128) Overlaps(c Bitvector128) (bool, error) {
 	lenB, lenC := b.Len(), c.Len()
 	if b.Len() != c.Len() {
-		return false, ErrBitvectorDifferentLength
+		return false, lengthMismatch("Bitvector128.Overlaps", ErrBitvectorDifferentLength, b.Len(), c.Len())
 	}
 
 	if lenB == 0 || lenC == 0 {
@@ -169,7 +169,7 @@ func (b Bitvector128) Overlaps(c Bitvector128) (bool, error) {
 // Or returns the OR result of the two bitfields. This method will return an error if the bitlists are not the same length.
 func (b Bitvector128) Or(c Bitvector128) (Bitvector128, error) {
 	if b.Len() != c.Len() {
-		return nil, ErrBitvectorDifferentLength
+		return nil, lengthMismatch("Bitvector128.Or", ErrBitvectorDifferentLength, b.Len(), c.Len())
 	}
 
 	ret := make([]byte, len(b))
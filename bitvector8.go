@@ -96,10 +96,10 @@ func (b Bitvector8) BitIndices() []int {
 // bitlist. This method will return an error if bitlists are not the same length or not `bitvector8BitSize`.
 func (b Bitvector8) Contains(c Bitvector8) (bool, error) {
 	if b.Len() != c.Len() {
-		return false, ErrBitvectorDifferentLength
+		return false, lengthMismatch("Bitvector8.Contains", ErrBitvectorDifferentLength, b.Len(), c.Len())
 	}
 	if b.Len() != bitvector8BitSize {
-		return false, ErrWrongLen
+		return false, lengthMismatch("Bitvector8.Contains", ErrWrongLen, b.Len(), bitvector8BitSize)
 	}
 
 	// Combine the byte from b and c, then XOR them against b. If the result of this is non-zero, then we
@@ -111,10 +111,10 @@ func (b Bitvector8) Contains(c Bitvector8) (bool, error) {
 // bitlist. This method will return an error if bitlists are not the same length.
 func (b Bitvector8) Overlaps(c Bitvector8) (bool, error) {
 	if b.Len() != c.Len() {
-		return false, ErrBitvectorDifferentLength
+		return false, lengthMismatch("Bitvector8.Overlaps", ErrBitvectorDifferentLength, b.Len(), c.Len())
 	}
 	if b.Len() != bitvector8BitSize {
-		return false, ErrWrongLen
+		return false, lengthMismatch("Bitvector8.Overlaps", ErrWrongLen, b.Len(), bitvector8BitSize)
 	}
 
 	// Invert b and xor the byte from b and c, then and it against c. If the result is non-zero, then
@@ -126,10 +126,10 @@ func (b Bitvector8) Overlaps(c Bitvector8) (bool, error) {
 // Or returns the OR result of the two bitfields. This method will return an error if the bitlists are not the same length.
 func (b Bitvector8) Or(c Bitvector8) (Bitvector8, error) {
 	if b.Len() != c.Len() {
-		return nil, ErrBitvectorDifferentLength
+		return nil, lengthMismatch("Bitvector8.Or", ErrBitvectorDifferentLength, b.Len(), c.Len())
 	}
 	if b.Len() != bitvector8BitSize {
-		return nil, ErrWrongLen
+		return nil, lengthMismatch("Bitvector8.Or", ErrWrongLen, b.Len(), bitvector8BitSize)
 	}
 
 	return []byte{b[0] | c[0]}, nil
@@ -0,0 +1,148 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+// BloomFilter is a probabilistic set backed by a Bitlist64 of m bits. Every element sets k bits,
+// chosen by double hashing. Membership tests never return false negatives, and return false
+// positives with a probability that grows as the filter fills up.
+type BloomFilter struct {
+	k    uint64
+	bits *Bitlist64
+}
+
+// NewBloomFilter creates an empty bloom filter of m bits, which sets k bits per element.
+// This method will return an error if m or k is zero.
+func NewBloomFilter(m, k uint64) (*BloomFilter, error) {
+	if m == 0 || k == 0 {
+		return nil, fmt.Errorf("NewBloomFilter: %w (m %d, k %d)", ErrBloomFilterParameters, m, k)
+	}
+	return &BloomFilter{k: k, bits: NewBitlist64(m)}, nil
+}
+
+// NewBloomFilterForCapacity creates an empty bloom filter sized to hold n elements with a false
+// positive rate of at most p, using the optimal m = -n*ln(p)/ln(2)^2 and k = m/n*ln(2).
+// This method will return an error if n is zero, or p is not within (0, 1).
+func NewBloomFilterForCapacity(n uint64, p float64) (*BloomFilter, error) {
+	if n == 0 || !(p > 0 && p < 1) {
+		return nil, fmt.Errorf("NewBloomFilterForCapacity: %w (n %d, p %v)", ErrBloomFilterParameters, n, p)
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(n)*math.Ln2))
+	return NewBloomFilter(uint64(m), uint64(k))
+}
+
+// M returns the number of bits of the filter.
+func (f *BloomFilter) M() uint64 {
+	return f.bits.Len()
+}
+
+// K returns the number of bits set per element.
+func (f *BloomFilter) K() uint64 {
+	return f.k
+}
+
+// Bits returns the bits backing the filter. The bitlist is shared with the filter.
+func (f *BloomFilter) Bits() *Bitlist64 {
+	return f.bits
+}
+
+// Add inserts the element into the filter.
+func (f *BloomFilter) Add(data []byte) {
+	h1, h2 := bloomHashes(data)
+	m := f.bits.Len()
+	for i := uint64(0); i < f.k; i++ {
+		f.bits.SetBitAt((h1+i*h2)%m, true)
+	}
+}
+
+// Contains returns true if the element may have been added to the filter, and false if it has
+// definitely not been added.
+func (f *BloomFilter) Contains(data []byte) bool {
+	h1, h2 := bloomHashes(data)
+	m := f.bits.Len()
+	for i := uint64(0); i < f.k; i++ {
+		if !f.bits.BitAt((h1 + i*h2) % m) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new filter holding the elements of both filters.
+// This method will return an error if the filters have different m or k.
+func (f *BloomFilter) Union(g *BloomFilter) (*BloomFilter, error) {
+	if f.k != g.k {
+		return nil, fmt.Errorf("BloomFilter.Union: %w (%d != %d)", ErrBloomFilterMismatch, f.k, g.k)
+	}
+	bits, err := f.bits.Or(g.bits)
+	if err != nil {
+		return nil, err
+	}
+	return &BloomFilter{k: f.k, bits: bits}, nil
+}
+
+// Intersection returns a new filter approximating the elements present in both filters. Elements
+// of both filters are always reported as present, but the false positive rate is higher than for
+// a filter built from the intersection directly.
+// This method will return an error if the filters have different m or k.
+func (f *BloomFilter) Intersection(g *BloomFilter) (*BloomFilter, error) {
+	if f.k != g.k {
+		return nil, fmt.Errorf("BloomFilter.Intersection: %w (%d != %d)", ErrBloomFilterMismatch, f.k, g.k)
+	}
+	bits, err := f.bits.And(g.bits)
+	if err != nil {
+		return nil, err
+	}
+	return &BloomFilter{k: f.k, bits: bits}, nil
+}
+
+// EstimatedCount estimates the number of distinct elements added to the filter from the number of
+// set bits x, as -m/k * ln(1 - x/m). The estimate is +Inf once all bits are set.
+func (f *BloomFilter) EstimatedCount() float64 {
+	m := float64(f.bits.Len())
+	x := float64(f.bits.Count())
+	return -m / float64(f.k) * math.Log1p(-x/m)
+}
+
+// MarshalBinary encodes the filter as the uvarint k, followed by the envelope of its bits written
+// by Bitlist64.MarshalBinary.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	bits, err := f.bits.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(binary.AppendUvarint(nil, f.k), bits...), nil
+}
+
+// UnmarshalBinary decodes a filter encoded with MarshalBinary.
+// This method will return an error if the encoding is malformed, or holds a zero m or k.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	k, n := binary.Uvarint(data)
+	if n <= 0 {
+		return fmt.Errorf("BloomFilter.UnmarshalBinary: %w: bad k", ErrInvalidEncoding)
+	}
+	bits := &Bitlist64{}
+	if err := bits.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	if k == 0 || bits.Len() == 0 {
+		return fmt.Errorf("BloomFilter.UnmarshalBinary: %w (m %d, k %d)", ErrBloomFilterParameters, bits.Len(), k)
+	}
+	f.k, f.bits = k, bits
+	return nil
+}
+
+// bloomHashes returns the two hashes of the element used for double hashing, taken from the halves
+// of its 128-bit FNV-1a hash. The second hash is odd, so that it is never zero.
+func bloomHashes(data []byte) (uint64, uint64) {
+	h := fnv.New128a()
+	_, _ = h.Write(data)
+	var sum [16]byte
+	h.Sum(sum[:0])
+	return binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:]) | 1
+}
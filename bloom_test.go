@@ -0,0 +1,150 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+func bloomKey(i uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, i)
+}
+
+func TestBloomFilter(t *testing.T) {
+	n := uint64(10000)
+	f, err := NewBloomFilterForCapacity(n, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.M() != 95851 || f.K() != 7 {
+		t.Errorf("NewBloomFilterForCapacity() = m %d, k %d, wanted m 95851, k 7", f.M(), f.K())
+	}
+
+	for i := uint64(0); i < n; i++ {
+		f.Add(bloomKey(i))
+	}
+	for i := uint64(0); i < n; i++ {
+		if !f.Contains(bloomKey(i)) {
+			t.Fatalf("Contains(%d) = false after Add()", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := n; i < 11*n; i++ {
+		if f.Contains(bloomKey(i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / float64(10*n); rate > 0.015 {
+		t.Errorf("false positive rate = %v, wanted about 0.01", rate)
+	}
+
+	if got := f.EstimatedCount(); math.Abs(got-float64(n))/float64(n) > 0.05 {
+		t.Errorf("EstimatedCount() = %v, wanted about %d", got, n)
+	}
+}
+
+func TestBloomFilter_UnionIntersection(t *testing.T) {
+	a, err := NewBloomFilter(4096, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBloomFilter(4096, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(0); i < 200; i++ {
+		a.Add(bloomKey(i))
+	}
+	for i := uint64(100); i < 300; i++ {
+		b.Add(bloomKey(i))
+	}
+
+	union, err := a.Union(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intersection, err := a.Intersection(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(0); i < 300; i++ {
+		if !union.Contains(bloomKey(i)) {
+			t.Errorf("union does not contain %d", i)
+		}
+		if i >= 100 && i < 200 && !intersection.Contains(bloomKey(i)) {
+			t.Errorf("intersection does not contain %d", i)
+		}
+	}
+	if got := union.EstimatedCount(); math.Abs(got-300) > 15 {
+		t.Errorf("union EstimatedCount() = %v, wanted about 300", got)
+	}
+	// The inputs are left unchanged.
+	if a.Contains(bloomKey(250)) && a.Contains(bloomKey(251)) && a.Contains(bloomKey(252)) {
+		t.Error("Union() modified its receiver")
+	}
+
+	c, err := NewBloomFilter(4096, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = a.Union(c); !errors.Is(err, ErrBloomFilterMismatch) {
+		t.Errorf("Union() error = %v, wanted %v", err, ErrBloomFilterMismatch)
+	}
+	d, err := NewBloomFilter(2048, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Intersection(d); !errors.Is(err, ErrBitlistDifferentLength) {
+		t.Errorf("Intersection() error = %v, wanted %v", err, ErrBitlistDifferentLength)
+	}
+}
+
+func TestBloomFilter_MarshalBinary(t *testing.T) {
+	f, err := NewBloomFilter(1000, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Add([]byte("peer-1"))
+	f.Add([]byte("peer-2"))
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &BloomFilter{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got.K() != 3 || !got.Bits().Equal(*f.Bits()) {
+		t.Errorf("UnmarshalBinary() = k %d, bits %v, wanted k 3, bits %v", got.K(), got.Bits(), f.Bits())
+	}
+	if !got.Contains([]byte("peer-1")) || !got.Contains([]byte("peer-2")) {
+		t.Error("decoded filter lost elements")
+	}
+
+	if err := got.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("UnmarshalBinary() error = %v, wanted %v", err, ErrChecksumMismatch)
+	}
+	if err := got.UnmarshalBinary([]byte{0x80}); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("UnmarshalBinary() error = %v, wanted %v", err, ErrInvalidEncoding)
+	}
+	if err := got.UnmarshalBinary(append([]byte{0}, data[1:]...)); !errors.Is(err, ErrBloomFilterParameters) {
+		t.Errorf("UnmarshalBinary() error = %v, wanted %v", err, ErrBloomFilterParameters)
+	}
+}
+
+func TestNewBloomFilter_Errors(t *testing.T) {
+	if _, err := NewBloomFilter(0, 1); !errors.Is(err, ErrBloomFilterParameters) {
+		t.Errorf("NewBloomFilter() error = %v, wanted %v", err, ErrBloomFilterParameters)
+	}
+	if _, err := NewBloomFilter(1, 0); !errors.Is(err, ErrBloomFilterParameters) {
+		t.Errorf("NewBloomFilter() error = %v, wanted %v", err, ErrBloomFilterParameters)
+	}
+	for _, p := range []float64{0, 1, math.NaN()} {
+		if _, err := NewBloomFilterForCapacity(100, p); !errors.Is(err, ErrBloomFilterParameters) {
+			t.Errorf("NewBloomFilterForCapacity(100, %v) error = %v, wanted %v", p, err, ErrBloomFilterParameters)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package bitfield
+
+// checkedBitfield is implemented by all the bitfields of this package.
+type checkedBitfield interface {
+	TryBitAt(idx uint64) (bool, error)
+	TrySetBitAt(idx uint64, val bool) error
+}
+
+var (
+	_ = checkedBitfield(Bitlist{})
+	_ = checkedBitfield(&Bitlist64{})
+	_ = checkedBitfield(Bitvector4{})
+	_ = checkedBitfield(Bitvector8{})
+	_ = checkedBitfield(Bitvector32{})
+	_ = checkedBitfield(Bitvector64{})
+	_ = checkedBitfield(Bitvector128{})
+	_ = checkedBitfield(Bitvector256{})
+	_ = checkedBitfield(Bitvector512{})
+)
+
+// Strict wraps a bitfield so that out of range access panics instead of being silently ignored.
+// It is meant to be used in tests, to catch index translation bugs which would otherwise turn into
+// a false BitAt or a no-op SetBitAt. The panic value is an *IndexOutOfRangeError (or a
+// *LengthMismatchError for malformed bitvectors).
+type Strict struct {
+	Bitfield
+}
+
+var _ = Bitfield(Strict{})
+
+// NewStrict wraps the bitfield into a Strict bitfield.
+func NewStrict(b Bitfield) Strict {
+	return Strict{Bitfield: b}
+}
+
+// BitAt returns the bit value at the given index, and panics if the index is out of range.
+func (s Strict) BitAt(idx uint64) bool {
+	if c, ok := s.Bitfield.(checkedBitfield); ok {
+		val, err := c.TryBitAt(idx)
+		if err != nil {
+			panic(err)
+		}
+		return val
+	}
+	if idx >= s.Len() {
+		panic(indexOutOfRange("Strict.BitAt", idx, s.Len()))
+	}
+	return s.Bitfield.BitAt(idx)
+}
+
+// SetBitAt sets the bit at the given index to val, and panics if the index is out of range.
+func (s Strict) SetBitAt(idx uint64, val bool) {
+	if c, ok := s.Bitfield.(checkedBitfield); ok {
+		if err := c.TrySetBitAt(idx, val); err != nil {
+			panic(err)
+		}
+		return
+	}
+	if idx >= s.Len() {
+		panic(indexOutOfRange("Strict.SetBitAt", idx, s.Len()))
+	}
+	s.Bitfield.SetBitAt(idx, val)
+}
+
+// TryBitAt returns the bit value at the given index.
+// This method will return an error if the index is out of the bitlist bounds.
+func (b Bitlist) TryBitAt(idx uint64) (bool, error) {
+	if size := b.Len(); idx >= size {
+		return false, indexOutOfRange("Bitlist.TryBitAt", idx, size)
+	}
+	return b.BitAt(idx), nil
+}
+
+// TrySetBitAt sets the bit at the given index to the given value.
+// This method will return an error if the index is out of the bitlist bounds.
+func (b Bitlist) TrySetBitAt(idx uint64, val bool) error {
+	if size := b.Len(); idx >= size {
+		return indexOutOfRange("Bitlist.TrySetBitAt", idx, size)
+	}
+	b.SetBitAt(idx, val)
+	return nil
+}
+
+// TryBitAt returns the bit value at the given index.
+// This method will return an error if the index is out of the bitlist bounds.
+func (b *Bitlist64) TryBitAt(idx uint64) (bool, error) {
+	if idx >= b.size {
+		return false, indexOutOfRange("Bitlist64.TryBitAt", idx, b.size)
+	}
+	return b.BitAt(idx), nil
+}
+
+// TrySetBitAt sets the bit at the given index to the given value.
+// This method will return an error if the index is out of the bitlist bounds.
+func (b *Bitlist64) TrySetBitAt(idx uint64, val bool) error {
+	if idx >= b.size {
+		return indexOutOfRange("Bitlist64.TrySetBitAt", idx, b.size)
+	}
+	b.SetBitAt(idx, val)
+	return nil
+}
+
+// TryBitAt returns the bit value at the given index. This method will return an error if the index
+// is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector4) TryBitAt(idx uint64) (bool, error) {
+	if err := checkVectorLen("Bitvector4.TryBitAt", b, bitvector4ByteSize); err != nil {
+		return false, err
+	}
+	if idx >= bitvector4BitSize {
+		return false, indexOutOfRange("Bitvector4.TryBitAt", idx, bitvector4BitSize)
+	}
+	return b.BitAt(idx), nil
+}
+
+// TrySetBitAt sets the bit at the given index to the given value. This method will return an error
+// if the index is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector4) TrySetBitAt(idx uint64, val bool) error {
+	if err := checkVectorLen("Bitvector4.TrySetBitAt", b, bitvector4ByteSize); err != nil {
+		return err
+	}
+	if idx >= bitvector4BitSize {
+		return indexOutOfRange("Bitvector4.TrySetBitAt", idx, bitvector4BitSize)
+	}
+	b.SetBitAt(idx, val)
+	return nil
+}
+
+// TryBitAt returns the bit value at the given index. This method will return an error if the index
+// is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector8) TryBitAt(idx uint64) (bool, error) {
+	if err := checkVectorLen("Bitvector8.TryBitAt", b, bitvector8ByteSize); err != nil {
+		return false, err
+	}
+	if idx >= bitvector8BitSize {
+		return false, indexOutOfRange("Bitvector8.TryBitAt", idx, bitvector8BitSize)
+	}
+	return b.BitAt(idx), nil
+}
+
+// TrySetBitAt sets the bit at the given index to the given value. This method will return an error
+// if the index is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector8) TrySetBitAt(idx uint64, val bool) error {
+	if err := checkVectorLen("Bitvector8.TrySetBitAt", b, bitvector8ByteSize); err != nil {
+		return err
+	}
+	if idx >= bitvector8BitSize {
+		return indexOutOfRange("Bitvector8.TrySetBitAt", idx, bitvector8BitSize)
+	}
+	b.SetBitAt(idx, val)
+	return nil
+}
+
+// TryBitAt returns the bit value at the given index. This method will return an error if the index
+// is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector32) TryBitAt(idx uint64) (bool, error) {
+	if err := checkVectorLen("Bitvector32.TryBitAt", b, bitvector32ByteSize); err != nil {
+		return false, err
+	}
+	if idx >= bitvector32BitSize {
+		return false, indexOutOfRange("Bitvector32.TryBitAt", idx, bitvector32BitSize)
+	}
+	return b.BitAt(idx), nil
+}
+
+// TrySetBitAt sets the bit at the given index to the given value. This method will return an error
+// if the index is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector32) TrySetBitAt(idx uint64, val bool) error {
+	if err := checkVectorLen("Bitvector32.TrySetBitAt", b, bitvector32ByteSize); err != nil {
+		return err
+	}
+	if idx >= bitvector32BitSize {
+		return indexOutOfRange("Bitvector32.TrySetBitAt", idx, bitvector32BitSize)
+	}
+	b.SetBitAt(idx, val)
+	return nil
+}
+
+// TryBitAt returns the bit value at the given index. This method will return an error if the index
+// is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector64) TryBitAt(idx uint64) (bool, error) {
+	if err := checkVectorLen("Bitvector64.TryBitAt", b, bitvector64ByteSize); err != nil {
+		return false, err
+	}
+	if idx >= bitvector64BitSize {
+		return false, indexOutOfRange("Bitvector64.TryBitAt", idx, bitvector64BitSize)
+	}
+	return b.BitAt(idx), nil
+}
+
+// TrySetBitAt sets the bit at the given index to the given value. This method will return an error
+// if the index is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector64) TrySetBitAt(idx uint64, val bool) error {
+	if err := checkVectorLen("Bitvector64.TrySetBitAt", b, bitvector64ByteSize); err != nil {
+		return err
+	}
+	if idx >= bitvector64BitSize {
+		return indexOutOfRange("Bitvector64.TrySetBitAt", idx, bitvector64BitSize)
+	}
+	b.SetBitAt(idx, val)
+	return nil
+}
+
+// TryBitAt returns the bit value at the given index. This method will return an error if the index
+// is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector128) TryBitAt(idx uint64) (bool, error) {
+	if err := checkVectorLen("Bitvector128.TryBitAt", b, bitvector128ByteSize); err != nil {
+		return false, err
+	}
+	if idx >= bitvector128BitSize {
+		return false, indexOutOfRange("Bitvector128.TryBitAt", idx, bitvector128BitSize)
+	}
+	return b.BitAt(idx), nil
+}
+
+// TrySetBitAt sets the bit at the given index to the given value. This method will return an error
+// if the index is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector128) TrySetBitAt(idx uint64, val bool) error {
+	if err := checkVectorLen("Bitvector128.TrySetBitAt", b, bitvector128ByteSize); err != nil {
+		return err
+	}
+	if idx >= bitvector128BitSize {
+		return indexOutOfRange("Bitvector128.TrySetBitAt", idx, bitvector128BitSize)
+	}
+	b.SetBitAt(idx, val)
+	return nil
+}
+
+// TryBitAt returns the bit value at the given index. This method will return an error if the index
+// is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector256) TryBitAt(idx uint64) (bool, error) {
+	if err := checkVectorLen("Bitvector256.TryBitAt", b, bitvector256ByteSize); err != nil {
+		return false, err
+	}
+	if idx >= bitvector256BitSize {
+		return false, indexOutOfRange("Bitvector256.TryBitAt", idx, bitvector256BitSize)
+	}
+	return b.BitAt(idx), nil
+}
+
+// TrySetBitAt sets the bit at the given index to the given value. This method will return an error
+// if the index is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector256) TrySetBitAt(idx uint64, val bool) error {
+	if err := checkVectorLen("Bitvector256.TrySetBitAt", b, bitvector256ByteSize); err != nil {
+		return err
+	}
+	if idx >= bitvector256BitSize {
+		return indexOutOfRange("Bitvector256.TrySetBitAt", idx, bitvector256BitSize)
+	}
+	b.SetBitAt(idx, val)
+	return nil
+}
+
+// TryBitAt returns the bit value at the given index. This method will return an error if the index
+// is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector512) TryBitAt(idx uint64) (bool, error) {
+	if err := checkVectorLen("Bitvector512.TryBitAt", b, bitvector512ByteSize); err != nil {
+		return false, err
+	}
+	if idx >= bitvector512BitSize {
+		return false, indexOutOfRange("Bitvector512.TryBitAt", idx, bitvector512BitSize)
+	}
+	return b.BitAt(idx), nil
+}
+
+// TrySetBitAt sets the bit at the given index to the given value. This method will return an error
+// if the index is out of the bitvector bounds, or if the bitvector is not of the correct length.
+func (b Bitvector512) TrySetBitAt(idx uint64, val bool) error {
+	if err := checkVectorLen("Bitvector512.TrySetBitAt", b, bitvector512ByteSize); err != nil {
+		return err
+	}
+	if idx >= bitvector512BitSize {
+		return indexOutOfRange("Bitvector512.TrySetBitAt", idx, bitvector512BitSize)
+	}
+	b.SetBitAt(idx, val)
+	return nil
+}
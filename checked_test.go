@@ -0,0 +1,84 @@
+package bitfield
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTryBitAt(t *testing.T) {
+	bitfields := []Bitfield{
+		NewBitlist(0),
+		NewBitlist(13),
+		NewBitlist64(0),
+		NewBitlist64(100),
+		NewBitvector4(),
+		NewBitvector8(),
+		NewBitvector32(),
+		NewBitvector64(),
+		NewBitvector128(),
+		NewBitvector256(),
+		NewBitvector512(),
+	}
+
+	for _, b := range bitfields {
+		t.Run(fmt.Sprintf("%T(%d)", b, b.Len()), func(t *testing.T) {
+			c := b.(checkedBitfield)
+			for idx := uint64(0); idx < b.Len(); idx++ {
+				if err := c.TrySetBitAt(idx, idx%2 == 0); err != nil {
+					t.Fatalf("TrySetBitAt(%d) returned error: %v", idx, err)
+				}
+				if got, err := c.TryBitAt(idx); got != (idx%2 == 0) || err != nil {
+					t.Fatalf("TryBitAt(%d) = %t, %v, wanted %t", idx, got, err, idx%2 == 0)
+				}
+			}
+
+			var rangeErr *IndexOutOfRangeError
+			if _, err := c.TryBitAt(b.Len()); !errors.As(err, &rangeErr) || rangeErr.Index != b.Len() || rangeErr.Len != b.Len() {
+				t.Errorf("TryBitAt(%d) error = %v, wanted index out of range", b.Len(), err)
+			}
+			if err := c.TrySetBitAt(b.Len(), true); !errors.Is(err, ErrIndexOutOfRange) {
+				t.Errorf("TrySetBitAt(%d) error = %v, wanted %v", b.Len(), err, ErrIndexOutOfRange)
+			}
+			if b.Count() != (b.Len()+1)/2 {
+				t.Errorf("Count() = %d, wanted %d", b.Count(), (b.Len()+1)/2)
+			}
+		})
+	}
+
+	t.Run("wrong bitvector length", func(t *testing.T) {
+		if _, err := (Bitvector32{0x01}).TryBitAt(0); !errors.Is(err, ErrWrongLen) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+		}
+		if err := (Bitvector32{0x01}).TrySetBitAt(0, true); !errors.Is(err, ErrWrongLen) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+		}
+	})
+}
+
+func TestStrict(t *testing.T) {
+	expectPanic := func(t *testing.T, name string, f func()) {
+		defer func() {
+			r := recover()
+			err, ok := r.(error)
+			if !ok || !errors.Is(err, ErrIndexOutOfRange) {
+				t.Errorf("%s: recovered %v, wanted an index out of range panic", name, r)
+			}
+		}()
+		f()
+	}
+
+	b := NewStrict(NewBitlist(10))
+	b.SetBitAt(9, true)
+	if !b.BitAt(9) || b.Count() != 1 || b.Len() != 10 {
+		t.Errorf("Strict bitfield does not behave as the wrapped one: %v", b.BitIndices())
+	}
+	expectPanic(t, "BitAt", func() { b.BitAt(10) })
+	expectPanic(t, "SetBitAt", func() { b.SetBitAt(10, true) })
+
+	// Implementations from outside the package are checked against their length.
+	other := NewStrict(NewStrict(NewBitvector8()))
+	other.SetBitAt(7, true)
+	expectPanic(t, "BitAt", func() { other.BitAt(8) })
+	expectPanic(t, "SetBitAt", func() { other.SetBitAt(8, true) })
+}
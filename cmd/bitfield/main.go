@@ -0,0 +1,172 @@
+// Command bitfield decodes and inspects bitfields, such as the aggregation bits and participation
+// flags found in logs.
+//
+// Usage:
+//
+//	bitfield [flags] <input>
+//	bitfield [flags] <input> or|and|xor|contains|overlaps <input>
+//	bitfield [flags] not <input>
+//
+// Inputs can be given as hex encoded SSZ bytes ("0x0f01"), as a JSON array of booleans or 0/1
+// numbers ("[1,0,1]"), or in range notation ("16:0-3,7"), where the optional length is followed by a
+// comma separated list of set indices and inclusive ranges.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "bitfield: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// options holds the command line flags.
+type options struct {
+	kind    kind
+	format  string
+	width   int
+	unicode bool
+	ssz     bool
+	root    bool
+	limit   uint64
+}
+
+// run parses the command line arguments, and writes the result to w.
+func run(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("bitfield", flag.ContinueOnError)
+	fs.SetOutput(w)
+	typeName := fs.String("type", "bitlist", "bitfield type: bitlist, bitlist64 or bitvector{4,8,32,64,128,256,512}")
+	format := fs.String("format", "auto", "input format: auto, hex, json or range")
+	width := fs.Int("width", 32, "number of bits per row of the grid, 0 to disable the grid")
+	unicode := fs.Bool("unicode", false, "draw the grid with Unicode blocks instead of # and .")
+	ssz := fs.Bool("ssz", false, "print the SSZ encoding")
+	root := fs.Bool("root", false, "print the SSZ hash tree root")
+	limit := fs.Uint64("limit", 0, "maximum length of a bitlist, used for the hash tree root (default: its length)")
+	fs.Usage = func() {
+		fmt.Fprintln(w, "Usage: bitfield [flags] <input> [or|and|xor|contains|overlaps <input>]")
+		fmt.Fprintln(w, "       bitfield [flags] not <input>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	k, ok := kinds[*typeName]
+	if !ok {
+		return fmt.Errorf("unknown type %q", *typeName)
+	}
+	opts := options{kind: k, format: *format, width: *width, unicode: *unicode, ssz: *ssz, root: *root, limit: *limit}
+
+	switch args := fs.Args(); {
+	case len(args) == 1:
+		b, err := parse(args[0], opts)
+		if err != nil {
+			return err
+		}
+		return describe(w, b, opts)
+	case len(args) == 2 && args[0] == "not":
+		b, err := parse(args[1], opts)
+		if err != nil {
+			return err
+		}
+		return describe(w, b.Not(), opts)
+	case len(args) == 3:
+		return apply(w, args[1], args[0], args[2], opts)
+	default:
+		fs.Usage()
+		return errors.New("wrong number of arguments")
+	}
+}
+
+// apply parses both inputs, applies the binary operation, and writes the result to w.
+func apply(w io.Writer, op, left, right string, opts options) error {
+	a, err := parse(left, opts)
+	if err != nil {
+		return err
+	}
+	b, err := parse(right, opts)
+	if err != nil {
+		return err
+	}
+
+	var ret *bitfield.Bitlist64
+	switch op {
+	case "or":
+		ret, err = a.Or(b)
+	case "and":
+		ret, err = a.And(b)
+	case "xor":
+		ret, err = a.Xor(b)
+	case "contains", "overlaps":
+		var ok bool
+		if op == "contains" {
+			ok, err = a.Contains(b)
+		} else {
+			ok, err = a.Overlaps(b)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, ok)
+		return err
+	default:
+		return fmt.Errorf("unknown operation %q", op)
+	}
+	if err != nil {
+		return err
+	}
+	return describe(w, ret, opts)
+}
+
+// describe writes the length, count, indices and grid of the bitfield to w, followed by its SSZ
+// encoding and hash tree root if requested.
+func describe(w io.Writer, b *bitfield.Bitlist64, opts options) error {
+	set, unset := indices(b)
+	fmt.Fprintf(w, "type:   %s\n", opts.kind.name)
+	fmt.Fprintf(w, "length: %d\n", b.Len())
+	fmt.Fprintf(w, "count:  %d\n", b.Count())
+	fmt.Fprintf(w, "set:    %v\n", set)
+	fmt.Fprintf(w, "unset:  %v\n", unset)
+	if opts.ssz {
+		fmt.Fprintf(w, "ssz:    %#x\n", encode(b, opts.kind))
+	}
+	if opts.root {
+		r, err := hashTreeRoot(b, opts.kind, opts.limit)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "root:   %#x\n", r)
+	}
+	if opts.width > 0 && b.Len() > 0 {
+		g, err := bitfield.Renderer{Width: opts.width, ASCII: !opts.unicode}.Render(b)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "grid:")
+		_, err = io.WriteString(w, g)
+		return err
+	}
+	return nil
+}
+
+// indices returns the set and the unset indices of the bitfield.
+func indices(b *bitfield.Bitlist64) ([]uint64, []uint64) {
+	set, unset := []uint64{}, []uint64{}
+	for i := uint64(0); i < b.Len(); i++ {
+		if b.BitAt(i) {
+			set = append(set, i)
+		} else {
+			unset = append(unset, i)
+		}
+	}
+	return set, unset
+}
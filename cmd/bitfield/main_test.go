@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "hex bitlist",
+			args: []string{"-ssz", "0x0f01"},
+			want: []string{"length: 8\n", "count:  4\n", "set:    [0 1 2 3]\n", "unset:  [4 5 6 7]\n", "ssz:    0x0f01\n", "0  ####....\n"},
+		},
+		{
+			name: "unicode grid",
+			args: []string{"-unicode", "-width", "4", "0x0f01"},
+			want: []string{"grid:\n   0\n0  ████\n4  ░░░░\n"},
+		},
+		{
+			name: "json bitlist",
+			args: []string{"-width", "0", "[1, 0, true, false]"},
+			want: []string{"length: 4\n", "set:    [0 2]\n"},
+		},
+		{
+			name: "range bitvector",
+			args: []string{"-type", "bitvector8", "-ssz", "0-2,7"},
+			want: []string{"type:   bitvector8\n", "set:    [0 1 2 7]\n", "ssz:    0x87\n"},
+		},
+		{
+			name: "or",
+			args: []string{"8:0-3", "or", "8:2-5"},
+			want: []string{"set:    [0 1 2 3 4 5]\n"},
+		},
+		{
+			name: "and",
+			args: []string{"8:0-3", "and", "8:2-5"},
+			want: []string{"set:    [2 3]\n"},
+		},
+		{
+			name: "xor",
+			args: []string{"8:0-3", "xor", "8:2-5"},
+			want: []string{"set:    [0 1 4 5]\n"},
+		},
+		{
+			name: "not",
+			args: []string{"not", "0x0f01"},
+			want: []string{"set:    [4 5 6 7]\n"},
+		},
+		{
+			name: "contains",
+			args: []string{"8:0-3", "contains", "8:1"},
+			want: []string{"true\n"},
+		},
+		{
+			name: "overlaps",
+			args: []string{"8:0-3", "overlaps", "8:4-7"},
+			want: []string{"false\n"},
+		},
+		{
+			name:    "different lengths",
+			args:    []string{"8:0", "or", "9:0"},
+			wantErr: "different lengths",
+		},
+		{
+			name:    "unknown operation",
+			args:    []string{"8:0", "nand", "8:0"},
+			wantErr: "unknown operation",
+		},
+		{
+			name:    "bitlist range without length",
+			args:    []string{"0-3"},
+			wantErr: "needs a length",
+		},
+		{
+			name:    "bitvector of the wrong length",
+			args:    []string{"-type", "bitvector4", "[1,0,1]"},
+			wantErr: "must have 4 bits",
+		},
+		{
+			name:    "index out of range",
+			args:    []string{"8:6-8"},
+			wantErr: "bad range",
+		},
+		{
+			name:    "missing length bit",
+			args:    []string{"0x0f00"},
+			wantErr: "missing length bit",
+		},
+		{
+			name:    "unknown type",
+			args:    []string{"-type", "bitvector16", "0x00"},
+			wantErr: "unknown type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(tt.args, &out)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("run() error = %v, wanted %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output %q does not contain %q", out.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestHashTreeRoot(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		kind  string
+		limit uint64
+		want  string
+	}{
+		{
+			name:  "bitlist without limit",
+			input: "0x0f01",
+			kind:  "bitlist",
+			want:  "ac0d43079c4f10cade6386f382829a4a00e4d9832cb66a068969c761bce57d96",
+		},
+		{
+			name:  "bitlist with limit",
+			input: "[1,0,1]",
+			kind:  "bitlist",
+			limit: 2048,
+			want:  "8e67833502313f86bb672bbf94fd3904995a799dd856005e75d69e5e93be0433",
+		},
+		{
+			name:  "empty bitlist",
+			input: "0x01",
+			kind:  "bitlist",
+			limit: 2048,
+			want:  "e8e527e84f666163a90ef900e013f56b0a4d020148b2224057b719f351b003a6",
+		},
+		{
+			name:  "single chunk bitvector",
+			input: "0x05",
+			kind:  "bitvector8",
+			want:  "0500000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			name:  "two chunk bitvector",
+			input: "0,511",
+			kind:  "bitvector512",
+			want:  "32ef790e6268d0a3383d1627bc2932fa37d9a41ba4c6f1850e572a61a5c32414",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options{kind: kinds[tt.kind], format: "auto"}
+			b, err := parse(tt.input, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			root, err := hashTreeRoot(b, opts.kind, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(root[:]); got != tt.want {
+				t.Errorf("hashTreeRoot() = %s, wanted %s", got, tt.want)
+			}
+		})
+	}
+
+	b, err := parse("16:0", options{kind: kinds["bitlist"], format: "auto"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hashTreeRoot(b, kinds["bitlist"], 8); err == nil {
+		t.Error("hashTreeRoot() should fail when the length exceeds the limit")
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// kind describes one of the bitfield types of the package.
+type kind struct {
+	name string
+	// size is the length of a bitvector, and zero for bitlists.
+	size uint64
+}
+
+// isVector returns true if the kind is a bitvector.
+func (k kind) isVector() bool {
+	return k.size != 0
+}
+
+var kinds = map[string]kind{
+	"bitlist":      {name: "bitlist"},
+	"bitlist64":    {name: "bitlist64"},
+	"bitvector4":   {name: "bitvector4", size: bitfield.NewBitvector4().Len()},
+	"bitvector8":   {name: "bitvector8", size: bitfield.NewBitvector8().Len()},
+	"bitvector32":  {name: "bitvector32", size: bitfield.NewBitvector32().Len()},
+	"bitvector64":  {name: "bitvector64", size: bitfield.NewBitvector64().Len()},
+	"bitvector128": {name: "bitvector128", size: bitfield.NewBitvector128().Len()},
+	"bitvector256": {name: "bitvector256", size: bitfield.NewBitvector256().Len()},
+	"bitvector512": {name: "bitvector512", size: bitfield.NewBitvector512().Len()},
+}
+
+// parse decodes an input of the given format into a bitlist holding its bits.
+func parse(s string, opts options) (*bitfield.Bitlist64, error) {
+	format := opts.format
+	if format == "auto" {
+		switch s = strings.TrimSpace(s); {
+		case strings.HasPrefix(s, "0x"):
+			format = "hex"
+		case strings.HasPrefix(s, "["):
+			format = "json"
+		default:
+			format = "range"
+		}
+	}
+
+	switch format {
+	case "hex":
+		return parseHex(s, opts.kind)
+	case "json":
+		return parseJSON(s, opts.kind)
+	case "range":
+		return parseRange(s, opts.kind)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// parseHex decodes hex encoded SSZ bytes. Bitlists must hold a length bit.
+func parseHex(s string, k kind) (*bitfield.Bitlist64, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("bad hex input: %w", err)
+	}
+
+	if !k.isVector() {
+		var b bitfield.Bitlist
+		if err := b.Unmarshal(data); err != nil {
+			return nil, err
+		}
+		if len(b) == 0 {
+			return nil, fmt.Errorf("empty bitlist input")
+		}
+		return b.ToBitlist64()
+	}
+
+	if want := (k.size + 7) / 8; uint64(len(data)) != want {
+		return nil, fmt.Errorf("%s must be %d bytes, got %d", k.name, want, len(data))
+	}
+	ret := bitfield.NewBitlist64(k.size)
+	for i := uint64(0); i < k.size; i++ {
+		ret.SetBitAt(i, data[i/8]&(1<<(i%8)) != 0)
+	}
+	return ret, nil
+}
+
+// parseJSON decodes a JSON array of booleans or 0/1 numbers, one per bit.
+func parseJSON(s string, k kind) (*bitfield.Bitlist64, error) {
+	var values []interface{}
+	if err := json.Unmarshal([]byte(s), &values); err != nil {
+		return nil, fmt.Errorf("bad JSON input: %w", err)
+	}
+	if err := checkLen(k, uint64(len(values))); err != nil {
+		return nil, err
+	}
+
+	ret := bitfield.NewBitlist64(uint64(len(values)))
+	for i, v := range values {
+		switch v {
+		case true, float64(1):
+			ret.SetBitAt(uint64(i), true)
+		case false, float64(0):
+		default:
+			return nil, fmt.Errorf("bad JSON value at index %d: %v", i, v)
+		}
+	}
+	return ret, nil
+}
+
+// parseRange decodes the range notation, i.e. an optional length and a colon, followed by a comma
+// separated list of indices and inclusive ranges of indices, such as "16:0-3,7". The length can only
+// be omitted for bitvectors.
+func parseRange(s string, k kind) (*bitfield.Bitlist64, error) {
+	size := k.size
+	if before, after, found := strings.Cut(s, ":"); found {
+		n, err := strconv.ParseUint(strings.TrimSpace(before), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad length %q", before)
+		}
+		if err := checkLen(k, n); err != nil {
+			return nil, err
+		}
+		size, s = n, after
+	} else if !k.isVector() {
+		return nil, fmt.Errorf("range input for a %s needs a length, such as \"16:0-3,7\"", k.name)
+	}
+
+	ret := bitfield.NewBitlist64(size)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(item, "-")
+		start, err := strconv.ParseUint(strings.TrimSpace(first), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad index %q", item)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.ParseUint(strings.TrimSpace(last), 10, 64); err != nil {
+				return nil, fmt.Errorf("bad range %q", item)
+			}
+		}
+		if err := ret.SetRange(start, end+1); err != nil {
+			return nil, fmt.Errorf("bad range %q: %w", item, err)
+		}
+	}
+	return ret, nil
+}
+
+// checkLen returns an error if a bitvector kind is given a length other than its own.
+func checkLen(k kind, n uint64) error {
+	if k.isVector() && n != k.size {
+		return fmt.Errorf("%s must have %d bits, got %d", k.name, k.size, n)
+	}
+	return nil
+}
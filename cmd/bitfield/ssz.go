@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// bitsPerChunk is the number of bits packed in a 32 byte SSZ chunk.
+const bitsPerChunk = 256
+
+// encode returns the SSZ encoding of the bitfield. Bitlists end with a length bit, bitvectors are
+// packed in the minimal number of bytes.
+func encode(b *bitfield.Bitlist64, k kind) []byte {
+	if !k.isVector() {
+		return b.ToBitlist()
+	}
+	return packBits(b)
+}
+
+// packBits packs the bits in bytes, with the bit i stored as the bit i%8 of the byte i/8.
+func packBits(b *bitfield.Bitlist64) []byte {
+	ret := make([]byte, (b.Len()+7)/8)
+	for _, idx := range b.BitIndices() {
+		ret[idx/8] |= 1 << (idx % 8)
+	}
+	return ret
+}
+
+// hashTreeRoot returns the SSZ hash tree root of the bitfield. For bitlists, the limit is the
+// maximum length of the list type, and defaults to the length of the bitlist when zero.
+func hashTreeRoot(b *bitfield.Bitlist64, k kind, limit uint64) ([32]byte, error) {
+	packed := packBits(b)
+	if k.isVector() {
+		return merkleize(packed, (k.size+bitsPerChunk-1)/bitsPerChunk), nil
+	}
+
+	if limit == 0 {
+		limit = b.Len()
+	}
+	if b.Len() > limit {
+		return [32]byte{}, fmt.Errorf("bitlist length %d exceeds the limit %d", b.Len(), limit)
+	}
+	root := merkleize(packed, (limit+bitsPerChunk-1)/bitsPerChunk)
+
+	// Mix in the length, as a 32 byte little-endian integer.
+	var length [32]byte
+	binary.LittleEndian.PutUint64(length[:], b.Len())
+	return sha256.Sum256(append(root[:], length[:]...)), nil
+}
+
+// merkleize splits data into 32 byte chunks, pads them with zero chunks up to the next power of two
+// of limit, and returns the root of the resulting binary merkle tree.
+func merkleize(data []byte, limit uint64) [32]byte {
+	layer := make([][32]byte, (len(data)+31)/32)
+	for i := range layer {
+		copy(layer[i][:], data[i*32:])
+	}
+
+	depth := 0
+	if limit > 1 {
+		depth = bits.Len64(limit - 1)
+	}
+	// zero is the root of an empty subtree at the current depth.
+	var zero [32]byte
+	for d := 0; d < depth; d++ {
+		if len(layer) == 0 {
+			zero = sha256.Sum256(append(zero[:], zero[:]...))
+			continue
+		}
+		if len(layer)%2 == 1 {
+			layer = append(layer, zero)
+		}
+		next := make([][32]byte, len(layer)/2)
+		for i := range next {
+			next[i] = sha256.Sum256(append(layer[2*i][:], layer[2*i+1][:]...))
+		}
+		layer = next
+		zero = sha256.Sum256(append(zero[:], zero[:]...))
+	}
+
+	if len(layer) == 0 {
+		return zero
+	}
+	return layer[0]
+}
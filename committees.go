@@ -0,0 +1,77 @@
+package bitfield
+
+// maxCommitteesPerSlot is the number of committees a Bitvector64 of committee bits can select.
+const maxCommitteesPerSlot = bitvector64BitSize
+
+// SplitAggregationBits splits the aggregation bits of an EIP-7549 (Electra) attestation into
+// per-committee bitlists. The committee bits select which committees of the slot participate, and
+// the aggregation bits hold the concatenated bits of the selected committees, in the order of their
+// committee indices. The committeeSizes are the sizes of all the committees in the slot, indexed by
+// committee index.
+//
+// The returned slice is indexed by committee index as well, with nil entries for the committees
+// not selected by the committee bits. This method will return an error if a selected committee has
+// no size, or if the aggregation bits length is not the sum of the selected committee sizes.
+func SplitAggregationBits(committeeBits Bitvector64, aggregationBits Bitlist, committeeSizes []uint64) ([]Bitlist, error) {
+	if err := checkVectorLen("SplitAggregationBits", committeeBits, bitvector64ByteSize); err != nil {
+		return nil, err
+	}
+	if len(committeeSizes) > maxCommitteesPerSlot {
+		return nil, lengthMismatch("SplitAggregationBits", ErrTooManyCommittees, uint64(len(committeeSizes)), maxCommitteesPerSlot)
+	}
+
+	total := uint64(0)
+	for _, idx := range committeeBits.BitIndices() {
+		if idx >= len(committeeSizes) {
+			return nil, indexOutOfRange("SplitAggregationBits", uint64(idx), uint64(len(committeeSizes)))
+		}
+		total += committeeSizes[idx]
+	}
+	if total != aggregationBits.Len() {
+		return nil, lengthMismatch("SplitAggregationBits", ErrAggregationBitsLength, aggregationBits.Len(), total)
+	}
+
+	ret := make([]Bitlist, len(committeeSizes))
+	offset := uint64(0)
+	for _, idx := range committeeBits.BitIndices() {
+		committee, err := aggregationBits.Slice(offset, offset+committeeSizes[idx])
+		if err != nil {
+			return nil, err
+		}
+		ret[idx] = committee
+		offset += committeeSizes[idx]
+	}
+
+	return ret, nil
+}
+
+// MergeAggregationBits is the inverse of SplitAggregationBits: it combines per-committee bitlists,
+// indexed by committee index, into the committee bits and the concatenated aggregation bits of an
+// EIP-7549 (Electra) attestation. Empty (or nil) entries mark committees that do not participate.
+// The committeeSizes are the sizes of all the committees in the slot, indexed by committee index.
+//
+// This method will return an error if the number of committees does not match the number of
+// committee sizes, or if any of the provided bitlists does not have its committee's size.
+func MergeAggregationBits(committees []Bitlist, committeeSizes []uint64) (Bitvector64, Bitlist, error) {
+	if len(committees) != len(committeeSizes) {
+		return nil, nil, lengthMismatch("MergeAggregationBits", ErrAggregationBitsLength, uint64(len(committees)), uint64(len(committeeSizes)))
+	}
+	if len(committees) > maxCommitteesPerSlot {
+		return nil, nil, lengthMismatch("MergeAggregationBits", ErrTooManyCommittees, uint64(len(committees)), maxCommitteesPerSlot)
+	}
+
+	committeeBits := NewBitvector64()
+	selected := make([]Bitlist, 0, len(committees))
+	for idx, committee := range committees {
+		if len(committee) == 0 {
+			continue
+		}
+		if committee.Len() != committeeSizes[idx] {
+			return nil, nil, lengthMismatch("MergeAggregationBits", ErrAggregationBitsLength, committee.Len(), committeeSizes[idx])
+		}
+		committeeBits.SetBitAt(uint64(idx), true)
+		selected = append(selected, committee)
+	}
+
+	return committeeBits, NewBitlist(0).Concat(selected...), nil
+}
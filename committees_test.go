@@ -0,0 +1,85 @@
+package bitfield
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAggregationBits_SplitMerge(t *testing.T) {
+	committeeSizes := []uint64{5, 9, 3, 12}
+
+	committee1 := NewBitlist(9)
+	committee1.SetBitAt(0, true)
+	committee1.SetBitAt(8, true)
+	committee3 := NewBitlist(12)
+	committee3.SetBitAt(4, true)
+	committees := []Bitlist{nil, committee1, nil, committee3}
+
+	committeeBits, aggregationBits, err := MergeAggregationBits(committees, committeeSizes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 3}; !reflect.DeepEqual(committeeBits.BitIndices(), want) {
+		t.Errorf("committee bits = %v, wanted %v", committeeBits.BitIndices(), want)
+	}
+	if aggregationBits.Len() != 21 {
+		t.Errorf("aggregation bits length = %d, wanted 21", aggregationBits.Len())
+	}
+	if want := []int{0, 8, 13}; !reflect.DeepEqual(aggregationBits.BitIndices(), want) {
+		t.Errorf("aggregation bits = %v, wanted %v", aggregationBits.BitIndices(), want)
+	}
+
+	split, err := SplitAggregationBits(committeeBits, aggregationBits, committeeSizes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(split) != len(committees) {
+		t.Fatalf("SplitAggregationBits() returned %d committees, wanted %d", len(split), len(committees))
+	}
+	for i := range committees {
+		if !bytes.Equal(split[i], committees[i]) {
+			t.Errorf("committee %d = %#x, wanted %#x", i, split[i], committees[i])
+		}
+	}
+}
+
+func TestAggregationBits_Errors(t *testing.T) {
+	committeeSizes := []uint64{5, 9}
+
+	t.Run("SplitAggregationBits()", func(t *testing.T) {
+		committeeBits := NewBitvector64()
+		committeeBits.SetBitAt(0, true)
+		committeeBits.SetBitAt(1, true)
+
+		if _, err := SplitAggregationBits(committeeBits, NewBitlist(13), committeeSizes); !errors.Is(err, ErrAggregationBitsLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrAggregationBitsLength, err)
+		}
+		if _, err := SplitAggregationBits(committeeBits, NewBitlist(15), committeeSizes); !errors.Is(err, ErrAggregationBitsLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrAggregationBitsLength, err)
+		}
+		committeeBits.SetBitAt(2, true)
+		if _, err := SplitAggregationBits(committeeBits, NewBitlist(14), committeeSizes); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+		}
+		if _, err := SplitAggregationBits(Bitvector64{0x01}, NewBitlist(5), committeeSizes); !errors.Is(err, ErrWrongLen) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+		}
+		if _, err := SplitAggregationBits(NewBitvector64(), NewBitlist(0), make([]uint64, 65)); !errors.Is(err, ErrTooManyCommittees) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrTooManyCommittees, err)
+		}
+	})
+
+	t.Run("MergeAggregationBits()", func(t *testing.T) {
+		if _, _, err := MergeAggregationBits([]Bitlist{NewBitlist(5)}, committeeSizes); !errors.Is(err, ErrAggregationBitsLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrAggregationBitsLength, err)
+		}
+		if _, _, err := MergeAggregationBits([]Bitlist{NewBitlist(5), NewBitlist(8)}, committeeSizes); !errors.Is(err, ErrAggregationBitsLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrAggregationBitsLength, err)
+		}
+		if _, _, err := MergeAggregationBits(make([]Bitlist, 65), make([]uint64, 65)); !errors.Is(err, ErrTooManyCommittees) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrTooManyCommittees, err)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// BitfieldDiff holds the changes between two versions of a bitfield of the same length.
+type BitfieldDiff struct {
+	// Len is the length of both versions of the bitfield.
+	Len uint64
+	// Added holds the indices of the bits that were set, in increasing order.
+	Added []uint64
+	// Removed holds the indices of the bits that were cleared, in increasing order.
+	Removed []uint64
+}
+
+// Diff returns the indices of the bits that are set in to but not in from, and the indices of the
+// bits that are set in from but not in to. Both bitfields can be of any type, but must have the same
+// length. Two Bitlist64 are compared a word at a time, and two byte-backed bitfields a byte at a
+// time. Other combinations fall back to BitAt.
+func Diff(from, to Bitfield) (*BitfieldDiff, error) {
+	if from.Len() != to.Len() {
+		return nil, lengthMismatch("Diff", ErrBitfieldDifferentLength, from.Len(), to.Len())
+	}
+
+	d := &BitfieldDiff{Len: from.Len(), Added: []uint64{}, Removed: []uint64{}}
+	if a, ok := from.(*Bitlist64); ok {
+		if b, ok := to.(*Bitlist64); ok {
+			diffWords(d, a.data, b.data)
+			return d, nil
+		}
+	}
+
+	fromBytes, err := bitfieldBytes("Diff", from)
+	if err != nil {
+		return nil, err
+	}
+	toBytes, err := bitfieldBytes("Diff", to)
+	if err != nil {
+		return nil, err
+	}
+	if fromBytes != nil && toBytes != nil {
+		diffWords(d, fromBytes, toBytes)
+		return d, nil
+	}
+
+	for i := uint64(0); i < d.Len; i++ {
+		switch a, b := from.BitAt(i), to.BitAt(i); {
+		case b && !a:
+			d.Added = append(d.Added, i)
+		case a && !b:
+			d.Removed = append(d.Removed, i)
+		}
+	}
+	return d, nil
+}
+
+// diffWords fills d with the differences between the first d.Len bits of a and b.
+func diffWords[T word](d *BitfieldDiff, a, b []T) {
+	w := wordBits[T]()
+	numWords := (d.Len + w - 1) / w
+	for i := uint64(0); i < numWords; i++ {
+		x, y := a[i], b[i]
+		if i == numWords-1 && d.Len%w != 0 {
+			mask := ^T(0) >> (w - d.Len%w)
+			x, y = x&mask, y&mask
+		}
+		d.Added = appendIndices(d.Added, i*w, uint64(y&^x))
+		d.Removed = appendIndices(d.Removed, i*w, uint64(x&^y))
+	}
+}
+
+// appendIndices appends the offset of each bit set in word, starting from the least significant
+// bit.
+func appendIndices(ret []uint64, offset, word uint64) []uint64 {
+	for word != 0 {
+		ret = append(ret, offset+uint64(bits.TrailingZeros64(word)))
+		word &= word - 1
+	}
+	return ret
+}
+
+// Patch applies the diff to dst in place, so that a bitfield equal to from becomes equal to to.
+// The bits listed in d.Added are set and the bits listed in d.Removed are cleared, regardless of
+// their current value. dst is left unchanged if an error is returned.
+// This method will return an error if dst is not of the diff length, if the diff holds an out of
+// range index, or if an index is both added and removed.
+func Patch(dst Bitfield, d *BitfieldDiff) error {
+	if dst.Len() != d.Len {
+		return lengthMismatch("Patch", ErrBitfieldDifferentLength, dst.Len(), d.Len)
+	}
+	if err := checkIndices("Patch", d.Added, d.Len); err != nil {
+		return err
+	}
+	if err := checkIndices("Patch", d.Removed, d.Len); err != nil {
+		return err
+	}
+	if err := d.checkDisjoint(); err != nil {
+		return fmt.Errorf("Patch: %w", err)
+	}
+
+	for _, idx := range d.Added {
+		dst.SetBitAt(idx, true)
+	}
+	for _, idx := range d.Removed {
+		dst.SetBitAt(idx, false)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the diff as the uvarint length, followed by the added and then the removed
+// indices. Each list of indices is encoded as its uvarint size, followed by the first index and the
+// gaps between consecutive indices as uvarints.
+// This method will return an error if the indices are not strictly increasing, if any index
+// exceeds the diff length, or if an index is both added and removed, as UnmarshalBinary would not
+// accept the encoding.
+func (d *BitfieldDiff) MarshalBinary() ([]byte, error) {
+	ret := binary.AppendUvarint(nil, d.Len)
+	var err error
+	if ret, err = appendIndexDeltas(ret, d.Added, d.Len); err != nil {
+		return nil, fmt.Errorf("BitfieldDiff.MarshalBinary: %w", err)
+	}
+	if ret, err = appendIndexDeltas(ret, d.Removed, d.Len); err != nil {
+		return nil, fmt.Errorf("BitfieldDiff.MarshalBinary: %w", err)
+	}
+	if err = d.checkDisjoint(); err != nil {
+		return nil, fmt.Errorf("BitfieldDiff.MarshalBinary: %w", err)
+	}
+	return ret, nil
+}
+
+// UnmarshalBinary decodes a diff encoded with MarshalBinary.
+// This method will return an error if the encoding is truncated, holds trailing bytes, any index
+// exceeds the diff length, or an index is both added and removed.
+func (d *BitfieldDiff) UnmarshalBinary(data []byte) error {
+	size, n := binary.Uvarint(data)
+	if n <= 0 {
+		return fmt.Errorf("BitfieldDiff.UnmarshalBinary: %w: bad length", ErrInvalidDiff)
+	}
+	data = data[n:]
+
+	added, data, err := readIndexDeltas(data, size, ErrInvalidDiff)
+	if err != nil {
+		return fmt.Errorf("BitfieldDiff.UnmarshalBinary: %w", err)
+	}
+	removed, data, err := readIndexDeltas(data, size, ErrInvalidDiff)
+	if err != nil {
+		return fmt.Errorf("BitfieldDiff.UnmarshalBinary: %w", err)
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("BitfieldDiff.UnmarshalBinary: %w: %d trailing bytes", ErrInvalidDiff, len(data))
+	}
+	decoded := BitfieldDiff{Len: size, Added: added, Removed: removed}
+	if err := decoded.checkDisjoint(); err != nil {
+		return fmt.Errorf("BitfieldDiff.UnmarshalBinary: %w", err)
+	}
+
+	*d = decoded
+	return nil
+}
+
+// checkDisjoint returns an error wrapping ErrInvalidDiff if an index is both added and removed, as
+// the diff would then depend on the order in which it is applied. Errors are left for the caller to
+// prefix with the name of the operation.
+func (d *BitfieldDiff) checkDisjoint() error {
+	added := make(map[uint64]struct{}, len(d.Added))
+	for _, idx := range d.Added {
+		added[idx] = struct{}{}
+	}
+	for _, idx := range d.Removed {
+		if _, ok := added[idx]; ok {
+			return fmt.Errorf("%w: index %d is both added and removed", ErrInvalidDiff, idx)
+		}
+	}
+	return nil
+}
+
+// appendIndexDeltas appends the varint delta encoding of a strictly increasing list of indices, all
+// smaller than size. Errors are left for the caller to prefix with the name of the operation.
+func appendIndexDeltas(ret []byte, indices []uint64, size uint64) ([]byte, error) {
+	ret = binary.AppendUvarint(ret, uint64(len(indices)))
+	for i, idx := range indices {
+		if idx >= size {
+			return nil, fmt.Errorf("%w: index %d out of range for %d bits", ErrInvalidDiff, idx, size)
+		}
+		if i == 0 {
+			ret = binary.AppendUvarint(ret, idx)
+			continue
+		}
+		if idx <= indices[i-1] {
+			return nil, fmt.Errorf("%w: indices are not strictly increasing (%d after %d)",
+				ErrInvalidDiff, idx, indices[i-1])
+		}
+		ret = binary.AppendUvarint(ret, idx-indices[i-1]-1)
+	}
+	return ret, nil
+}
+
+// readIndexDeltas decodes a list of indices encoded with appendIndexDeltas, and returns the
+// remaining data. Decoding errors wrap errInvalid, and are left for the caller to prefix with the
+// name of the operation.
+func readIndexDeltas(data []byte, size uint64, errInvalid error) ([]uint64, []byte, error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, nil, fmt.Errorf("%w: bad index count", errInvalid)
+	}
+	data = data[n:]
+	// Every index takes at least one byte, which bounds the allocation below.
+	if count > uint64(len(data)) || count > size {
+		return nil, nil, fmt.Errorf("%w: too many indices (%d)", errInvalid, count)
+	}
+
+	indices := make([]uint64, count)
+	next := uint64(0)
+	for i := range indices {
+		delta, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, nil, fmt.Errorf("%w: bad index", errInvalid)
+		}
+		data = data[n:]
+		idx := next + delta
+		if idx < next || idx >= size {
+			return nil, nil, fmt.Errorf("%w: index out of range for %d bits", errInvalid, size)
+		}
+		indices[i] = idx
+		next = idx + 1
+	}
+	return indices, data, nil
+}
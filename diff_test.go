@@ -0,0 +1,167 @@
+package bitfield
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to Bitfield
+		added    []uint64
+		removed  []uint64
+	}{
+		{
+			name:    "bitlist",
+			from:    Bitlist{0b00110101, 0b00000011},
+			to:      Bitlist{0b01010110, 0b00000010},
+			added:   []uint64{1, 6},
+			removed: []uint64{0, 5, 8},
+		},
+		{
+			name:    "bitlist64",
+			from:    NewBitlist64From([]uint64{0x8000000000000001, 0x01}),
+			to:      NewBitlist64From([]uint64{0x0000000000000003, 0x10}),
+			added:   []uint64{1, 68},
+			removed: []uint64{63, 64},
+		},
+		{
+			name:    "bitvector4 ignores unused bits",
+			from:    Bitvector4{0x05},
+			to:      Bitvector4{0xF6},
+			added:   []uint64{1},
+			removed: []uint64{0},
+		},
+		{
+			name:    "bitlist and bitvector8",
+			from:    Bitlist{0x01, 0x01},
+			to:      Bitvector8{0x80},
+			added:   []uint64{7},
+			removed: []uint64{0},
+		},
+		{
+			name: "bitlist and bitlist64",
+			from: Bitlist{0x06, 0x01},
+			to: func() Bitfield {
+				b := NewBitlist64From([]uint64{0x8000000000000001})
+				b.Truncate(8)
+				return b
+			}(),
+			added:   []uint64{0},
+			removed: []uint64{1, 2},
+		},
+		{
+			name:    "no changes",
+			from:    Bitvector32{0x01, 0x02, 0x03, 0x04},
+			to:      Bitvector32{0x01, 0x02, 0x03, 0x04},
+			added:   []uint64{},
+			removed: []uint64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Diff(tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(d.Added, tt.added) {
+				t.Errorf("Added = %v, wanted %v", d.Added, tt.added)
+			}
+			if !reflect.DeepEqual(d.Removed, tt.removed) {
+				t.Errorf("Removed = %v, wanted %v", d.Removed, tt.removed)
+			}
+
+			data, err := d.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := &BitfieldDiff{}
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, d) {
+				t.Errorf("UnmarshalBinary() = %+v, wanted %+v", decoded, d)
+			}
+
+			if err := Patch(tt.from, decoded); err != nil {
+				t.Fatal(err)
+			}
+			for i := uint64(0); i < tt.from.Len(); i++ {
+				if tt.from.BitAt(i) != tt.to.BitAt(i) {
+					t.Errorf("bit %d = %t after Patch(), wanted %t", i, tt.from.BitAt(i), tt.to.BitAt(i))
+				}
+			}
+		})
+	}
+}
+
+func TestDiff_Errors(t *testing.T) {
+	if _, err := Diff(NewBitlist(8), NewBitlist(9)); !errors.Is(err, ErrBitfieldDifferentLength) {
+		t.Errorf("Diff() error = %v, wanted %v", err, ErrBitfieldDifferentLength)
+	}
+	if _, err := Diff(Bitvector64{0x01}, NewBitlist(64)); !errors.Is(err, ErrWrongLen) {
+		t.Errorf("Diff() error = %v, wanted %v", err, ErrWrongLen)
+	}
+
+	b := NewBitlist(8)
+	if err := Patch(b, &BitfieldDiff{Len: 9}); !errors.Is(err, ErrBitfieldDifferentLength) {
+		t.Errorf("Patch() error = %v, wanted %v", err, ErrBitfieldDifferentLength)
+	}
+	if err := Patch(b, &BitfieldDiff{Len: 8, Added: []uint64{1, 8}}); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Patch() error = %v, wanted %v", err, ErrIndexOutOfRange)
+	}
+	if err := Patch(b, &BitfieldDiff{Len: 8, Added: []uint64{1, 3}, Removed: []uint64{3}}); !errors.Is(err, ErrInvalidDiff) {
+		t.Errorf("Patch() error = %v, wanted %v", err, ErrInvalidDiff)
+	}
+	if b.Count() != 0 {
+		t.Errorf("Patch() modified the bitfield on error: %x", b)
+	}
+
+	for _, d := range []*BitfieldDiff{
+		{Len: 8, Added: []uint64{3, 3}},
+		{Len: 8, Added: []uint64{3, 8}},
+		{Len: 8, Removed: []uint64{9}},
+		{Len: 8, Added: []uint64{1, 3}, Removed: []uint64{3}},
+	} {
+		if _, err := d.MarshalBinary(); !errors.Is(err, ErrInvalidDiff) {
+			t.Errorf("(%+v).MarshalBinary() error = %v, wanted %v", *d, err, ErrInvalidDiff)
+		}
+	}
+}
+
+func TestBitfieldDiff_UnmarshalBinary(t *testing.T) {
+	d := &BitfieldDiff{Len: 1000, Added: []uint64{0, 1, 500, 999}, Removed: []uint64{2, 200}}
+	data, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Length, then 4 added indices with deltas 0, 0, 498, 498, then 2 removed with deltas 2, 197.
+	want := []byte{0xe8, 0x07, 0x04, 0x00, 0x00, 0xf2, 0x03, 0xf2, 0x03, 0x02, 0x02, 0xc5, 0x01}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("MarshalBinary() = %x, wanted %x", data, want)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "truncated", data: data[:len(data)-1]},
+		{name: "trailing bytes", data: append(append([]byte{}, data...), 0x00)},
+		{name: "index out of range", data: []byte{0x08, 0x01, 0x08, 0x00}},
+		{name: "too many indices", data: []byte{0x08, 0x09, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "added and removed", data: []byte{0x08, 0x01, 0x03, 0x01, 0x03}},
+	}
+	for _, tt := range tests {
+		got := &BitfieldDiff{}
+		if err := got.UnmarshalBinary(tt.data); !errors.Is(err, ErrInvalidDiff) {
+			t.Errorf("%s: UnmarshalBinary() error = %v, wanted %v", tt.name, err, ErrInvalidDiff)
+		}
+		if got.Len != 0 || got.Added != nil || got.Removed != nil {
+			t.Errorf("%s: UnmarshalBinary() modified the diff on error: %+v", tt.name, *got)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+package bitfield
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = Bitlist{}
+	_ encoding.BinaryUnmarshaler = (*Bitlist)(nil)
+	_ encoding.BinaryMarshaler   = (*Bitlist64)(nil)
+	_ encoding.BinaryUnmarshaler = (*Bitlist64)(nil)
+	_ encoding.BinaryMarshaler   = Bitvector4{}
+	_ encoding.BinaryUnmarshaler = (*Bitvector4)(nil)
+	_ encoding.BinaryMarshaler   = Bitvector8{}
+	_ encoding.BinaryUnmarshaler = (*Bitvector8)(nil)
+	_ encoding.BinaryMarshaler   = Bitvector32{}
+	_ encoding.BinaryUnmarshaler = (*Bitvector32)(nil)
+	_ encoding.BinaryMarshaler   = Bitvector64{}
+	_ encoding.BinaryUnmarshaler = (*Bitvector64)(nil)
+	_ encoding.BinaryMarshaler   = Bitvector128{}
+	_ encoding.BinaryUnmarshaler = (*Bitvector128)(nil)
+	_ encoding.BinaryMarshaler   = Bitvector256{}
+	_ encoding.BinaryUnmarshaler = (*Bitvector256)(nil)
+	_ encoding.BinaryMarshaler   = Bitvector512{}
+	_ encoding.BinaryUnmarshaler = (*Bitvector512)(nil)
+)
+
+// The envelope written by MarshalBinary is laid out as follows:
+//
+//	type tag (1 byte) | version (1 byte) | body encoding (1 byte) | bit length (uvarint) | body | CRC32
+//
+// The CRC32 (IEEE) is stored as 4 little-endian bytes, and covers everything before it. The body
+// holds the bits in one of the following encodings, whichever is the smallest:
+//   - raw: the bits packed into bytes, with the bit i stored as the bit i%8 of the byte i/8.
+//   - RLE: the uvarint number of runs, followed by the uvarint length of each run. Runs alternate
+//     between unset and set bits, starting with unset bits. Only the first run can be empty.
+//   - sparse: the uvarint number of set bits, followed by the index of the first set bit and the
+//     gaps between consecutive set bits as uvarints.
+const (
+	envelopeVersion = 1
+	// envelopeHeaderSize is the size of the fixed part of the header, before the bit length.
+	envelopeHeaderSize = 3
+	// envelopeChecksumSize is the size of the CRC32 trailer.
+	envelopeChecksumSize = 4
+	// maxEnvelopeBits bounds the bit length accepted when decoding, since RLE and sparse bodies can
+	// describe much larger bitfields than their own size.
+	maxEnvelopeBits = uint64(1) << 32
+	// envelopeAnyLength is passed to decodeEnvelope by bitlists, which accept any bit length.
+	envelopeAnyLength = ^uint64(0)
+)
+
+// Type tags recorded in the envelope.
+const (
+	envelopeTagBitlist byte = iota + 1
+	envelopeTagBitlist64
+	envelopeTagBitvector4
+	envelopeTagBitvector8
+	envelopeTagBitvector32
+	envelopeTagBitvector64
+	envelopeTagBitvector128
+	envelopeTagBitvector256
+	envelopeTagBitvector512
+)
+
+// Body encodings recorded in the envelope.
+const (
+	envelopeBodyRaw byte = iota
+	envelopeBodyRLE
+	envelopeBodySparse
+)
+
+// encodeEnvelope returns the envelope of a bitfield of the given type and bit length. raw holds the
+// bits packed into bytes, with all bits at and above size cleared.
+func encodeEnvelope(tag byte, size uint64, raw []byte) []byte {
+	body, bodyEncoding := raw, envelopeBodyRaw
+	if rle := appendRLE(nil, size, raw); len(rle) < len(body) {
+		body, bodyEncoding = rle, envelopeBodyRLE
+	}
+	if sparse := appendSparse(nil, size, raw); len(sparse) < len(body) {
+		body, bodyEncoding = sparse, envelopeBodySparse
+	}
+
+	ret := make([]byte, 0, envelopeHeaderSize+binary.MaxVarintLen64+len(body)+envelopeChecksumSize)
+	ret = append(ret, tag, envelopeVersion, bodyEncoding)
+	ret = binary.AppendUvarint(ret, size)
+	ret = append(ret, body...)
+	return binary.LittleEndian.AppendUint32(ret, crc32.ChecksumIEEE(ret))
+}
+
+// decodeEnvelope decodes an envelope written by encodeEnvelope, and returns the bit length and the
+// bits packed into bytes. wantSize is the bit length expected by vector types, or envelopeAnyLength
+// for bitlists. The name of the calling method is used for error reporting.
+// This method will return an error if the checksum does not match, or if the envelope is malformed,
+// holds a bitfield of another type, or does not hold the expected bit length.
+func decodeEnvelope(name string, tag byte, wantSize uint64, data []byte) (uint64, []byte, error) {
+	if len(data) < envelopeHeaderSize+1+envelopeChecksumSize {
+		return 0, nil, fmt.Errorf("%s: %w: envelope too short (%d bytes)", name, ErrInvalidEncoding, len(data))
+	}
+	data, checksum := data[:len(data)-envelopeChecksumSize], data[len(data)-envelopeChecksumSize:]
+	if crc32.ChecksumIEEE(data) != binary.LittleEndian.Uint32(checksum) {
+		return 0, nil, fmt.Errorf("%s: %w", name, ErrChecksumMismatch)
+	}
+	if data[0] != tag {
+		return 0, nil, fmt.Errorf("%s: %w: unexpected type tag %d", name, ErrInvalidEncoding, data[0])
+	}
+	if data[1] != envelopeVersion {
+		return 0, nil, fmt.Errorf("%s: %w: unsupported version %d", name, ErrInvalidEncoding, data[1])
+	}
+	bodyEncoding := data[2]
+	size, n := binary.Uvarint(data[envelopeHeaderSize:])
+	if n <= 0 || size > maxEnvelopeBits {
+		return 0, nil, fmt.Errorf("%s: %w: bad bit length", name, ErrInvalidEncoding)
+	}
+	// The bit length is checked before decoding the body, which allocates for all the bits.
+	if wantSize != envelopeAnyLength && size != wantSize {
+		return 0, nil, lengthMismatch(name, ErrWrongLen, size, wantSize)
+	}
+	body := data[envelopeHeaderSize+n:]
+
+	var raw []byte
+	var err error
+	switch bodyEncoding {
+	case envelopeBodyRaw:
+		raw, err = readRaw(body, size)
+	case envelopeBodyRLE:
+		raw, err = readRLE(body, size)
+	case envelopeBodySparse:
+		raw, err = readSparse(body, size)
+	default:
+		err = fmt.Errorf("%w: unknown body encoding %d", ErrInvalidEncoding, bodyEncoding)
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return size, raw, nil
+}
+
+// readRaw decodes a raw body. Bits at and above size must be cleared.
+func readRaw(body []byte, size uint64) ([]byte, error) {
+	if uint64(len(body)) != (size+7)/8 {
+		return nil, fmt.Errorf("%w: raw body is %d bytes for %d bits", ErrInvalidEncoding, len(body), size)
+	}
+	if size%8 != 0 && body[len(body)-1]>>(size%8) != 0 {
+		return nil, fmt.Errorf("%w: bits set beyond the bit length", ErrInvalidEncoding)
+	}
+	raw := make([]byte, len(body))
+	copy(raw, body)
+	return raw, nil
+}
+
+// appendRLE appends the RLE body of the bits.
+func appendRLE(ret []byte, size uint64, raw []byte) []byte {
+	var runs []uint64
+	run, current := uint64(0), false
+	for i := uint64(0); i < size; i++ {
+		if bit := raw[i/8]&(1<<(i%8)) != 0; bit != current {
+			runs = append(runs, run)
+			run, current = 0, bit
+		}
+		run++
+	}
+	if size > 0 {
+		runs = append(runs, run)
+	}
+
+	ret = binary.AppendUvarint(ret, uint64(len(runs)))
+	for _, run := range runs {
+		ret = binary.AppendUvarint(ret, run)
+	}
+	return ret
+}
+
+// readRLE decodes an RLE body. The runs must add up to size. They are all read and checked before
+// allocating for the bits, so that a short body can't claim a huge bit length.
+func readRLE(body []byte, size uint64) ([]byte, error) {
+	count, n := binary.Uvarint(body)
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: bad run count", ErrInvalidEncoding)
+	}
+	body = body[n:]
+	// Every run takes at least one byte.
+	if count > uint64(len(body)) {
+		return nil, fmt.Errorf("%w: too many runs (%d)", ErrInvalidEncoding, count)
+	}
+	if count == 0 && size > 0 {
+		return nil, fmt.Errorf("%w: no runs for %d bits", ErrInvalidEncoding, size)
+	}
+
+	runs := make([]uint64, count)
+	offset := uint64(0)
+	for i := range runs {
+		run, n := binary.Uvarint(body)
+		if n <= 0 {
+			return nil, fmt.Errorf("%w: bad run", ErrInvalidEncoding)
+		}
+		body = body[n:]
+		if run == 0 && i != 0 {
+			return nil, fmt.Errorf("%w: empty run", ErrInvalidEncoding)
+		}
+		if run > size-offset {
+			return nil, fmt.Errorf("%w: runs exceed the bit length", ErrInvalidEncoding)
+		}
+		runs[i] = run
+		offset += run
+	}
+	if offset != size || len(body) != 0 {
+		return nil, fmt.Errorf("%w: runs do not match the bit length", ErrInvalidEncoding)
+	}
+
+	raw := make([]byte, (size+7)/8)
+	offset = 0
+	for i, run := range runs {
+		if i%2 == 1 {
+			for j := offset; j < offset+run; j++ {
+				raw[j/8] |= 1 << (j % 8)
+			}
+		}
+		offset += run
+	}
+	return raw, nil
+}
+
+// appendSparse appends the sparse body of the bits.
+func appendSparse(ret []byte, size uint64, raw []byte) []byte {
+	var indices []uint64
+	for i := uint64(0); i < size; i++ {
+		if raw[i/8]&(1<<(i%8)) != 0 {
+			indices = append(indices, i)
+		}
+	}
+	// Indices are strictly increasing and smaller than size, so this can't fail.
+	ret, _ = appendIndexDeltas(ret, indices, size)
+	return ret
+}
+
+// readSparse decodes a sparse body. The indices are all read and checked against size before
+// allocating for the bits.
+func readSparse(body []byte, size uint64) ([]byte, error) {
+	indices, rest, err := readIndexDeltas(body, size, ErrInvalidEncoding)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidEncoding, len(rest))
+	}
+	raw := make([]byte, (size+7)/8)
+	for _, idx := range indices {
+		raw[idx/8] |= 1 << (idx % 8)
+	}
+	return raw, nil
+}
+
+// MarshalBinary encodes the bitlist in a self-describing envelope, which records its type and exact
+// length along with a checksum.
+func (b Bitlist) MarshalBinary() ([]byte, error) {
+	size := b.Len()
+	raw := make([]byte, (size+7)/8)
+	copy(raw, b)
+	if size%8 != 0 {
+		raw[size/8] &= uint8(1<<(size%8)) - 1
+	}
+	return encodeEnvelope(envelopeTagBitlist, size, raw), nil
+}
+
+// UnmarshalBinary decodes a bitlist encoded with MarshalBinary.
+// This method will return an error if the data is not a valid Bitlist envelope.
+func (b *Bitlist) UnmarshalBinary(data []byte) error {
+	size, raw, err := decodeEnvelope("Bitlist.UnmarshalBinary", envelopeTagBitlist, envelopeAnyLength, data)
+	if err != nil {
+		return err
+	}
+	ret := make(Bitlist, size/8+1)
+	copy(ret, raw)
+	ret[size/8] |= uint8(1 << (size % 8))
+	*b = ret
+	return nil
+}
+
+// MarshalBinary encodes the bitlist in a self-describing envelope, which records its type and exact
+// length along with a checksum.
+func (b *Bitlist64) MarshalBinary() ([]byte, error) {
+	raw := make([]byte, (b.size+7)/8)
+	for i := range raw {
+		raw[i] = b.byteAt(uint64(i))
+	}
+	if b.size%8 != 0 {
+		raw[b.size/8] &= uint8(1<<(b.size%8)) - 1
+	}
+	return encodeEnvelope(envelopeTagBitlist64, b.size, raw), nil
+}
+
+// UnmarshalBinary decodes a bitlist encoded with MarshalBinary.
+// This method will return an error if the data is not a valid Bitlist64 envelope.
+func (b *Bitlist64) UnmarshalBinary(data []byte) error {
+	size, raw, err := decodeEnvelope("Bitlist64.UnmarshalBinary", envelopeTagBitlist64, envelopeAnyLength, data)
+	if err != nil {
+		return err
+	}
+	ret, err := NewBitlist64FromBytes(size, raw)
+	if err != nil {
+		return err
+	}
+	*b = *ret
+	return nil
+}
+
+// MarshalBinary encodes the bitvector in a self-describing envelope, which records its type and
+// length along with a checksum.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector4) MarshalBinary() ([]byte, error) {
+	if err := checkVectorLen("Bitvector4.MarshalBinary", b, bitvector4ByteSize); err != nil {
+		return nil, err
+	}
+	return encodeEnvelope(envelopeTagBitvector4, bitvector4BitSize, b.Bytes()), nil
+}
+
+// UnmarshalBinary decodes a bitvector encoded with MarshalBinary.
+// This method will return an error if the data is not a valid Bitvector4 envelope, or if it holds
+// a bitvector of another length.
+func (b *Bitvector4) UnmarshalBinary(data []byte) error {
+	_, raw, err := decodeEnvelope("Bitvector4.UnmarshalBinary", envelopeTagBitvector4, bitvector4BitSize, data)
+	if err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
+
+// MarshalBinary encodes the bitvector in a self-describing envelope, which records its type and
+// length along with a checksum.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector8) MarshalBinary() ([]byte, error) {
+	if err := checkVectorLen("Bitvector8.MarshalBinary", b, bitvector8ByteSize); err != nil {
+		return nil, err
+	}
+	return encodeEnvelope(envelopeTagBitvector8, bitvector8BitSize, b.Bytes()), nil
+}
+
+// UnmarshalBinary decodes a bitvector encoded with MarshalBinary.
+// This method will return an error if the data is not a valid Bitvector8 envelope, or if it holds
+// a bitvector of another length.
+func (b *Bitvector8) UnmarshalBinary(data []byte) error {
+	_, raw, err := decodeEnvelope("Bitvector8.UnmarshalBinary", envelopeTagBitvector8, bitvector8BitSize, data)
+	if err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
+
+// MarshalBinary encodes the bitvector in a self-describing envelope, which records its type and
+// length along with a checksum.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector32) MarshalBinary() ([]byte, error) {
+	if err := checkVectorLen("Bitvector32.MarshalBinary", b, bitvector32ByteSize); err != nil {
+		return nil, err
+	}
+	return encodeEnvelope(envelopeTagBitvector32, bitvector32BitSize, b.Bytes()), nil
+}
+
+// UnmarshalBinary decodes a bitvector encoded with MarshalBinary.
+// This method will return an error if the data is not a valid Bitvector32 envelope, or if it holds
+// a bitvector of another length.
+func (b *Bitvector32) UnmarshalBinary(data []byte) error {
+	_, raw, err := decodeEnvelope("Bitvector32.UnmarshalBinary", envelopeTagBitvector32, bitvector32BitSize, data)
+	if err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
+
+// MarshalBinary encodes the bitvector in a self-describing envelope, which records its type and
+// length along with a checksum.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector64) MarshalBinary() ([]byte, error) {
+	if err := checkVectorLen("Bitvector64.MarshalBinary", b, bitvector64ByteSize); err != nil {
+		return nil, err
+	}
+	return encodeEnvelope(envelopeTagBitvector64, bitvector64BitSize, b.Bytes()), nil
+}
+
+// UnmarshalBinary decodes a bitvector encoded with MarshalBinary.
+// This method will return an error if the data is not a valid Bitvector64 envelope, or if it holds
+// a bitvector of another length.
+func (b *Bitvector64) UnmarshalBinary(data []byte) error {
+	_, raw, err := decodeEnvelope("Bitvector64.UnmarshalBinary", envelopeTagBitvector64, bitvector64BitSize, data)
+	if err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
+
+// MarshalBinary encodes the bitvector in a self-describing envelope, which records its type and
+// length along with a checksum.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector128) MarshalBinary() ([]byte, error) {
+	if err := checkVectorLen("Bitvector128.MarshalBinary", b, bitvector128ByteSize); err != nil {
+		return nil, err
+	}
+	return encodeEnvelope(envelopeTagBitvector128, bitvector128BitSize, b.Bytes()), nil
+}
+
+// UnmarshalBinary decodes a bitvector encoded with MarshalBinary.
+// This method will return an error if the data is not a valid Bitvector128 envelope, or if it holds
+// a bitvector of another length.
+func (b *Bitvector128) UnmarshalBinary(data []byte) error {
+	_, raw, err := decodeEnvelope("Bitvector128.UnmarshalBinary", envelopeTagBitvector128, bitvector128BitSize, data)
+	if err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
+
+// MarshalBinary encodes the bitvector in a self-describing envelope, which records its type and
+// length along with a checksum.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector256) MarshalBinary() ([]byte, error) {
+	if err := checkVectorLen("Bitvector256.MarshalBinary", b, bitvector256ByteSize); err != nil {
+		return nil, err
+	}
+	return encodeEnvelope(envelopeTagBitvector256, bitvector256BitSize, b.Bytes()), nil
+}
+
+// UnmarshalBinary decodes a bitvector encoded with MarshalBinary.
+// This method will return an error if the data is not a valid Bitvector256 envelope, or if it holds
+// a bitvector of another length.
+func (b *Bitvector256) UnmarshalBinary(data []byte) error {
+	_, raw, err := decodeEnvelope("Bitvector256.UnmarshalBinary", envelopeTagBitvector256, bitvector256BitSize, data)
+	if err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
+
+// MarshalBinary encodes the bitvector in a self-describing envelope, which records its type and
+// length along with a checksum.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector512) MarshalBinary() ([]byte, error) {
+	if err := checkVectorLen("Bitvector512.MarshalBinary", b, bitvector512ByteSize); err != nil {
+		return nil, err
+	}
+	return encodeEnvelope(envelopeTagBitvector512, bitvector512BitSize, b.Bytes()), nil
+}
+
+// UnmarshalBinary decodes a bitvector encoded with MarshalBinary.
+// This method will return an error if the data is not a valid Bitvector512 envelope, or if it holds
+// a bitvector of another length.
+func (b *Bitvector512) UnmarshalBinary(data []byte) error {
+	_, raw, err := decodeEnvelope("Bitvector512.UnmarshalBinary", envelopeTagBitvector512, bitvector512BitSize, data)
+	if err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
@@ -0,0 +1,197 @@
+package bitfield
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"runtime"
+	"testing"
+)
+
+func TestBitlist_MarshalBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Bitlist
+		body byte
+	}{
+		{name: "empty", b: Bitlist{0x01}, body: envelopeBodyRaw},
+		{name: "dense", b: Bitlist{0x5A, 0xA5, 0x01}, body: envelopeBodyRaw},
+		{name: "trailing zeros", b: Bitlist{0x0F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, body: envelopeBodyRLE},
+		{name: "sparse", b: func() Bitlist {
+			b := NewBitlist(4096)
+			b.SetBitAt(5, true)
+			b.SetBitAt(1000, true)
+			b.SetBitAt(4000, true)
+			return b
+		}(), body: envelopeBodySparse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.b.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data[0] != envelopeTagBitlist || data[1] != envelopeVersion || data[2] != tt.body {
+				t.Errorf("header = %x, wanted tag %d, version %d, body %d",
+					data[:3], envelopeTagBitlist, envelopeVersion, tt.body)
+			}
+
+			var got Bitlist
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.b) || len(got) != len(tt.b) {
+				t.Errorf("UnmarshalBinary() = %x, wanted %x", got, tt.b)
+			}
+		})
+	}
+}
+
+func TestBitlist64_MarshalBinary(t *testing.T) {
+	for _, size := range []uint64{0, 1, 63, 64, 65, 1000, 10000} {
+		for _, step := range []uint64{1, 2, 97} {
+			b := NewBitlist64(size)
+			for i := uint64(0); i < size; i += step {
+				b.SetBitAt(i, true)
+			}
+
+			data, err := b.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := &Bitlist64{}
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("size %d, step %d: %v", size, step, err)
+			}
+			// Bytes() would have trimmed the trailing zeros, the envelope keeps the exact size.
+			if got.Len() != size || !got.Equal(*b) {
+				t.Errorf("size %d, step %d: UnmarshalBinary() = %v, wanted %v", size, step, got, b)
+			}
+		}
+	}
+}
+
+func TestBitvector_MarshalBinary(t *testing.T) {
+	b := NewBitvector512()
+	b.SetBitAt(3, true)
+	b.SetBitAt(511, true)
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[2] != envelopeBodySparse {
+		t.Errorf("body encoding = %d, wanted %d", data[2], envelopeBodySparse)
+	}
+	var got Bitvector512
+	if err = got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(b) {
+		t.Errorf("UnmarshalBinary() = %x, wanted %x", got, b)
+	}
+
+	// Unused bits of a Bitvector4 are not encoded.
+	data, err = Bitvector4{0xF5}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b4 Bitvector4
+	if err := b4.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if len(b4) != 1 || b4[0] != 0x05 {
+		t.Errorf("UnmarshalBinary() = %x, wanted 05", b4)
+	}
+
+	if _, err := (Bitvector8{0x01, 0x02}).MarshalBinary(); !errors.Is(err, ErrWrongLen) {
+		t.Errorf("MarshalBinary() error = %v, wanted %v", err, ErrWrongLen)
+	}
+}
+
+func TestUnmarshalBinary_Errors(t *testing.T) {
+	valid, err := Bitlist{0x0F, 0x01}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// withChecksum replaces the checksum of a modified envelope, to reach the checks behind it.
+	withChecksum := func(data []byte) []byte {
+		data = data[:len(data)-envelopeChecksumSize]
+		return binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	}
+	modified := func(i int, v byte) []byte {
+		data := append([]byte{}, valid...)
+		data[i] = v
+		return withChecksum(data)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		dst  encoding.BinaryUnmarshaler
+		want error
+	}{
+		{name: "empty", data: nil, dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "corrupted", data: append(append([]byte{}, valid[:4]...), valid[4]^0x01, valid[5], valid[6], valid[7], valid[8]), dst: &Bitlist{}, want: ErrChecksumMismatch},
+		{name: "wrong type", data: valid, dst: &Bitlist64{}, want: ErrInvalidEncoding},
+		{name: "wrong version", data: modified(1, 2), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "unknown body", data: modified(2, 7), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "bits beyond length", data: modified(3, 3), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "raw body too long", data: withChecksum(append([]byte{envelopeTagBitlist, envelopeVersion, envelopeBodyRaw, 8, 0x0F, 0x00}, 0, 0, 0, 0)), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "runs too long", data: withChecksum(append([]byte{envelopeTagBitlist, envelopeVersion, envelopeBodyRLE, 8, 2, 4, 5}, 0, 0, 0, 0)), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "runs too short", data: withChecksum(append([]byte{envelopeTagBitlist, envelopeVersion, envelopeBodyRLE, 8, 2, 4, 3}, 0, 0, 0, 0)), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "empty run", data: withChecksum(append([]byte{envelopeTagBitlist, envelopeVersion, envelopeBodyRLE, 8, 3, 4, 0, 4}, 0, 0, 0, 0)), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "sparse out of range", data: withChecksum(append([]byte{envelopeTagBitlist, envelopeVersion, envelopeBodySparse, 8, 1, 8}, 0, 0, 0, 0)), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "no runs", data: withChecksum(append([]byte{envelopeTagBitlist, envelopeVersion, envelopeBodyRLE, 8, 0}, 0, 0, 0, 0)), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "vector of another length", data: withChecksum(append([]byte{envelopeTagBitvector8, envelopeVersion, envelopeBodyRaw, 4, 0x0F}, 0, 0, 0, 0)), dst: &Bitvector8{}, want: ErrWrongLen},
+		{name: "too large", data: withChecksum(append([]byte{envelopeTagBitlist, envelopeVersion, envelopeBodyRLE, 0x80, 0x80, 0x80, 0x80, 0x20, 1, 0}, 0, 0, 0, 0)), dst: &Bitlist{}, want: ErrInvalidEncoding},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.dst.UnmarshalBinary(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("UnmarshalBinary() error = %v, wanted %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalBinary_HugeLength(t *testing.T) {
+	// envelope builds a valid envelope around a small body, claiming a bit length of 2^32 - 1.
+	envelope := func(tag, bodyEncoding byte, body ...byte) []byte {
+		data := []byte{tag, envelopeVersion, bodyEncoding}
+		data = binary.AppendUvarint(data, maxEnvelopeBits-1)
+		data = append(data, body...)
+		return binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		dst  encoding.BinaryUnmarshaler
+		want error
+	}{
+		{name: "vector rle", data: envelope(envelopeTagBitvector8, envelopeBodyRLE, 1, 0x08), dst: &Bitvector8{}, want: ErrWrongLen},
+		{name: "vector sparse", data: envelope(envelopeTagBitvector512, envelopeBodySparse, 0), dst: &Bitvector512{}, want: ErrWrongLen},
+		{name: "bitlist no runs", data: envelope(envelopeTagBitlist, envelopeBodyRLE, 0), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "bitlist short runs", data: envelope(envelopeTagBitlist, envelopeBodyRLE, 2, 4, 4), dst: &Bitlist{}, want: ErrInvalidEncoding},
+		{name: "bitlist raw", data: envelope(envelopeTagBitlist64, envelopeBodyRaw, 0xFF), dst: &Bitlist64{}, want: ErrInvalidEncoding},
+		{name: "bitlist sparse trailing bytes", data: envelope(envelopeTagBitlist64, envelopeBodySparse, 1, 0, 0), dst: &Bitlist64{}, want: ErrInvalidEncoding},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			err := tt.dst.UnmarshalBinary(tt.data)
+			runtime.ReadMemStats(&after)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("UnmarshalBinary() error = %v, wanted %v", err, tt.want)
+			}
+			if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+				t.Errorf("UnmarshalBinary() allocated %d bytes before failing", allocated)
+			}
+		})
+	}
+}
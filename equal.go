@@ -0,0 +1,354 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	// hashPrime1 and hashPrime2 are the multipliers used by Hash, taken from xxHash64.
+	hashPrime1 = 0x9E3779B185EBCA87
+	hashPrime2 = 0xC2B2AE3D27D4EB4F
+)
+
+// compareBits compares the first sizeA bits of a against the first sizeB bits of b, as strings of
+// bits starting from index 0. At the first index where the bits differ, the side with the bit unset
+// is the smaller one. If one is a prefix of the other, the shorter one is the smaller one. Words
+// missing from the end of a slice are treated as zero.
+func compareBits[T word](a []T, sizeA uint64, b []T, sizeB uint64) int {
+	w := wordBits[T]()
+	common := min(int(sizeA), int(sizeB))
+	numWords := (uint64(common) + w - 1) / w
+	for i := uint64(0); i < numWords; i++ {
+		x, y := wordAt(a, i), wordAt(b, i)
+		if i == numWords-1 && uint64(common)%w != 0 {
+			mask := ^T(0) >> (w - uint64(common)%w)
+			x, y = x&mask, y&mask
+		}
+		if diff := x ^ y; diff != 0 {
+			// Isolate the lowest differing bit.
+			lowest := diff & (^diff + 1)
+			if x&lowest != 0 {
+				return 1
+			}
+			return -1
+		}
+	}
+
+	switch {
+	case sizeA < sizeB:
+		return -1
+	case sizeA > sizeB:
+		return 1
+	}
+	return 0
+}
+
+// wordAt returns the word at the given index, or zero if the slice is too short.
+func wordAt[T word](data []T, i uint64) T {
+	if i >= uint64(len(data)) {
+		return 0
+	}
+	return data[i]
+}
+
+// hashWords returns a 64-bit non-cryptographic hash of the bitfield length and its words. The last
+// word is expected to have all bits at and above size cleared.
+func hashWords(size uint64, words func(yield func(w uint64))) uint64 {
+	h := size * hashPrime1
+	words(func(w uint64) {
+		h ^= w * hashPrime2
+		h = bits.RotateLeft64(h, 31) * hashPrime1
+	})
+
+	// Final avalanche, so that every input bit affects every output bit (fmix64 of MurmurHash3).
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// hashBytes hashes the first size bits of a byte-backed bitfield. The bytes are grouped into
+// little-endian uint64 words, so that the result matches the hash of a Bitlist64 with the same
+// bits.
+func hashBytes(data []byte, size uint64) uint64 {
+	numBytes := int((size + 7) / 8)
+	return hashWords(size, func(yield func(w uint64)) {
+		var buf [bytesInWord]byte
+		for i := 0; i < numBytes; i += bytesInWord {
+			end := min(i+bytesInWord, numBytes)
+			n := copy(buf[:], data[min(i, len(data)):min(end, len(data))])
+			clear(buf[n:])
+			w := binary.LittleEndian.Uint64(buf[:])
+			if end == numBytes && size%wordSize != 0 {
+				w &= allBitsSet >> (wordSize - size%wordSize)
+			}
+			yield(w)
+		}
+	})
+}
+
+// appendBitlistBytes appends the canonical byte representation of the first size bits, followed by
+// a length bit, to dst.
+func appendBitlistBytes(dst []byte, size uint64, byteAt func(i uint64) byte) []byte {
+	for i := uint64(0); i < size/8; i++ {
+		dst = append(dst, byteAt(i))
+	}
+	last := uint8(1 << (size % 8))
+	if size%8 != 0 {
+		last |= byteAt(size/8) & (uint8(1<<(size%8)) - 1)
+	}
+	return append(dst, last)
+}
+
+// vectorKey returns the key of a bitvector of size bits: its bytes cut or padded with zeros to the
+// size of the type, with the bits at and above size cleared.
+func vectorKey(b []byte, size uint64) string {
+	buf := make([]byte, (size+7)/8)
+	copy(buf, b)
+	if size%8 != 0 {
+		buf[len(buf)-1] &= uint8(1<<(size%8)) - 1
+	}
+	return string(buf)
+}
+
+// EqualBitlists returns true if the two bitlists hold the same bits, even though they are backed by
+// different types.
+func EqualBitlists(a Bitlist, b *Bitlist64) bool {
+	size := a.Len()
+	if size != b.Len() {
+		return false
+	}
+	for i := uint64(0); i < size/8; i++ {
+		if a[i] != b.byteAt(i) {
+			return false
+		}
+	}
+	if size%8 != 0 {
+		mask := uint8(1<<(size%8)) - 1
+		return a[size/8]&mask == b.byteAt(size/8)&mask
+	}
+	return true
+}
+
+// Equal returns true if both bitlists have the same length, and hold the same bits.
+func (b Bitlist) Equal(c Bitlist) bool {
+	return b.Compare(c) == 0
+}
+
+// Compare compares two bitlists bit by bit, starting from index 0. At the first index where the
+// bits differ, the bitlist with the bit unset sorts first. If one of the bitlists is a prefix of the
+// other, the shorter one sorts first. The result is 0 if b == c, -1 if b < c, and +1 if b > c.
+func (b Bitlist) Compare(c Bitlist) int {
+	return compareBits(b, b.Len(), c, c.Len())
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitlist. Equal bitlists have equal hashes,
+// and a Bitlist hashes to the same value as a Bitlist64 holding the same bits.
+func (b Bitlist) Hash() uint64 {
+	return hashBytes(b, b.Len())
+}
+
+// Key returns a string that uniquely identifies the length and the bits of the bitlist, suitable
+// for use as a map key. A Bitlist has the same key as a Bitlist64 holding the same bits.
+func (b Bitlist) Key() string {
+	return string(appendBitlistBytes(make([]byte, 0, b.Len()/8+1), b.Len(), func(i uint64) byte { return b[i] }))
+}
+
+// Equal returns true if both bitlists have the same length, and hold the same bits.
+// The argument is taken by value, as expected by protobuf generators for custom types.
+func (b *Bitlist64) Equal(c Bitlist64) bool {
+	return b.Compare(c) == 0
+}
+
+// Compare compares two bitlists bit by bit, starting from index 0. At the first index where the
+// bits differ, the bitlist with the bit unset sorts first. If one of the bitlists is a prefix of the
+// other, the shorter one sorts first. The result is 0 if b == c, -1 if b < c, and +1 if b > c.
+// The argument is taken by value, as expected by protobuf generators for custom types.
+func (b *Bitlist64) Compare(c Bitlist64) int {
+	return compareBits(b.data, b.size, c.data, c.size)
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitlist. Equal bitlists have equal hashes,
+// and a Bitlist64 hashes to the same value as a Bitlist holding the same bits.
+func (b *Bitlist64) Hash() uint64 {
+	return hashWords(b.size, func(yield func(w uint64)) {
+		for _, w := range b.data[:numWordsRequired(b.size)] {
+			yield(w)
+		}
+	})
+}
+
+// Key returns a string that uniquely identifies the length and the bits of the bitlist, suitable
+// for use as a map key. A Bitlist64 has the same key as a Bitlist holding the same bits.
+func (b *Bitlist64) Key() string {
+	return string(appendBitlistBytes(make([]byte, 0, b.size/8+1), b.size, b.byteAt))
+}
+
+// byteAt returns the i-th byte of the little-endian representation of the bitlist.
+func (b *Bitlist64) byteAt(i uint64) byte {
+	return byte(b.data[i>>bytesInWordLog2] >> ((i % bytesInWord) * 8))
+}
+
+// Equal returns true if both bitvectors hold the same bits.
+func (b Bitvector4) Equal(c Bitvector4) bool {
+	return b.Compare(c) == 0
+}
+
+// Compare compares two bitvectors bit by bit, starting from index 0. At the first index where the
+// bits differ, the bitvector with the bit unset sorts first. The result is 0 if b == c, -1 if
+// b < c, and +1 if b > c.
+func (b Bitvector4) Compare(c Bitvector4) int {
+	return compareBits(b, bitvector4BitSize, c, bitvector4BitSize)
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitvector. Equal bitvectors have equal hashes.
+func (b Bitvector4) Hash() uint64 {
+	return hashBytes(b, bitvector4BitSize)
+}
+
+// Key returns a string that uniquely identifies the bits of the bitvector, suitable for use as a
+// map key. A nil bitvector has the same key as a zero one, as they are equal.
+func (b Bitvector4) Key() string {
+	return vectorKey(b, bitvector4BitSize)
+}
+
+// Equal returns true if both bitvectors hold the same bits.
+func (b Bitvector8) Equal(c Bitvector8) bool {
+	return b.Compare(c) == 0
+}
+
+// Compare compares two bitvectors bit by bit, starting from index 0. At the first index where the
+// bits differ, the bitvector with the bit unset sorts first. The result is 0 if b == c, -1 if
+// b < c, and +1 if b > c.
+func (b Bitvector8) Compare(c Bitvector8) int {
+	return compareBits(b, bitvector8BitSize, c, bitvector8BitSize)
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitvector. Equal bitvectors have equal hashes.
+func (b Bitvector8) Hash() uint64 {
+	return hashBytes(b, bitvector8BitSize)
+}
+
+// Key returns a string that uniquely identifies the bits of the bitvector, suitable for use as a
+// map key. A nil bitvector has the same key as a zero one, as they are equal.
+func (b Bitvector8) Key() string {
+	return vectorKey(b, bitvector8BitSize)
+}
+
+// Equal returns true if both bitvectors hold the same bits.
+func (b Bitvector32) Equal(c Bitvector32) bool {
+	return b.Compare(c) == 0
+}
+
+// Compare compares two bitvectors bit by bit, starting from index 0. At the first index where the
+// bits differ, the bitvector with the bit unset sorts first. The result is 0 if b == c, -1 if
+// b < c, and +1 if b > c.
+func (b Bitvector32) Compare(c Bitvector32) int {
+	return compareBits(b, bitvector32BitSize, c, bitvector32BitSize)
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitvector. Equal bitvectors have equal hashes.
+func (b Bitvector32) Hash() uint64 {
+	return hashBytes(b, bitvector32BitSize)
+}
+
+// Key returns a string that uniquely identifies the bits of the bitvector, suitable for use as a
+// map key. A nil bitvector has the same key as a zero one, as they are equal.
+func (b Bitvector32) Key() string {
+	return vectorKey(b, bitvector32BitSize)
+}
+
+// Equal returns true if both bitvectors hold the same bits.
+func (b Bitvector64) Equal(c Bitvector64) bool {
+	return b.Compare(c) == 0
+}
+
+// Compare compares two bitvectors bit by bit, starting from index 0. At the first index where the
+// bits differ, the bitvector with the bit unset sorts first. The result is 0 if b == c, -1 if
+// b < c, and +1 if b > c.
+func (b Bitvector64) Compare(c Bitvector64) int {
+	return compareBits(b, bitvector64BitSize, c, bitvector64BitSize)
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitvector. Equal bitvectors have equal hashes.
+func (b Bitvector64) Hash() uint64 {
+	return hashBytes(b, bitvector64BitSize)
+}
+
+// Key returns a string that uniquely identifies the bits of the bitvector, suitable for use as a
+// map key. A nil bitvector has the same key as a zero one, as they are equal.
+func (b Bitvector64) Key() string {
+	return vectorKey(b, bitvector64BitSize)
+}
+
+// Equal returns true if both bitvectors hold the same bits.
+func (b Bitvector128) Equal(c Bitvector128) bool {
+	return b.Compare(c) == 0
+}
+
+// Compare compares two bitvectors bit by bit, starting from index 0. At the first index where the
+// bits differ, the bitvector with the bit unset sorts first. The result is 0 if b == c, -1 if
+// b < c, and +1 if b > c.
+func (b Bitvector128) Compare(c Bitvector128) int {
+	return compareBits(b, bitvector128BitSize, c, bitvector128BitSize)
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitvector. Equal bitvectors have equal hashes.
+func (b Bitvector128) Hash() uint64 {
+	return hashBytes(b, bitvector128BitSize)
+}
+
+// Key returns a string that uniquely identifies the bits of the bitvector, suitable for use as a
+// map key. A nil bitvector has the same key as a zero one, as they are equal.
+func (b Bitvector128) Key() string {
+	return vectorKey(b, bitvector128BitSize)
+}
+
+// Equal returns true if both bitvectors hold the same bits.
+func (b Bitvector256) Equal(c Bitvector256) bool {
+	return b.Compare(c) == 0
+}
+
+// Compare compares two bitvectors bit by bit, starting from index 0. At the first index where the
+// bits differ, the bitvector with the bit unset sorts first. The result is 0 if b == c, -1 if
+// b < c, and +1 if b > c.
+func (b Bitvector256) Compare(c Bitvector256) int {
+	return compareBits(b, bitvector256BitSize, c, bitvector256BitSize)
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitvector. Equal bitvectors have equal hashes.
+func (b Bitvector256) Hash() uint64 {
+	return hashBytes(b, bitvector256BitSize)
+}
+
+// Key returns a string that uniquely identifies the bits of the bitvector, suitable for use as a
+// map key. A nil bitvector has the same key as a zero one, as they are equal.
+func (b Bitvector256) Key() string {
+	return vectorKey(b, bitvector256BitSize)
+}
+
+// Equal returns true if both bitvectors hold the same bits.
+func (b Bitvector512) Equal(c Bitvector512) bool {
+	return b.Compare(c) == 0
+}
+
+// Compare compares two bitvectors bit by bit, starting from index 0. At the first index where the
+// bits differ, the bitvector with the bit unset sorts first. The result is 0 if b == c, -1 if
+// b < c, and +1 if b > c.
+func (b Bitvector512) Compare(c Bitvector512) int {
+	return compareBits(b, bitvector512BitSize, c, bitvector512BitSize)
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitvector. Equal bitvectors have equal hashes.
+func (b Bitvector512) Hash() uint64 {
+	return hashBytes(b, bitvector512BitSize)
+}
+
+// Key returns a string that uniquely identifies the bits of the bitvector, suitable for use as a
+// map key. A nil bitvector has the same key as a zero one, as they are equal.
+func (b Bitvector512) Key() string {
+	return vectorKey(b, bitvector512BitSize)
+}
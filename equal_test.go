@@ -0,0 +1,152 @@
+package bitfield
+
+import (
+	"testing"
+)
+
+func TestBitlist_Compare(t *testing.T) {
+	tests := []struct {
+		a, b Bitlist
+		want int
+	}{
+		{a: Bitlist{0x01}, b: Bitlist{0x01}, want: 0},
+		{a: Bitlist{0x02}, b: Bitlist{0x01}, want: 1},
+		{a: Bitlist{0x02}, b: Bitlist{0x03}, want: -1},
+		{a: Bitlist{0x13}, b: Bitlist{0x15}, want: 1},
+		{a: Bitlist{0x15}, b: Bitlist{0x13}, want: -1},
+		{a: Bitlist{0x1F}, b: Bitlist{0x1F}, want: 0},
+		{a: Bitlist{0x0F}, b: Bitlist{0x1F}, want: -1},
+		{a: Bitlist{0xFF, 0x01}, b: Bitlist{0x7F, 0x02}, want: 1},
+		{a: Bitlist{0x00, 0x81}, b: Bitlist{0x00, 0x82}, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Compare(tt.b); got != tt.want {
+			t.Errorf("(%x).Compare(%x) = %d, wanted %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := tt.b.Compare(tt.a); got != -tt.want {
+			t.Errorf("(%x).Compare(%x) = %d, wanted %d", tt.b, tt.a, got, -tt.want)
+		}
+		if got := tt.a.Equal(tt.b); got != (tt.want == 0) {
+			t.Errorf("(%x).Equal(%x) = %t, wanted %t", tt.a, tt.b, got, tt.want == 0)
+		}
+
+		a64, err := tt.a.ToBitlist64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b64, err := tt.b.ToBitlist64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a64.Compare(*b64); got != tt.want {
+			t.Errorf("(%v).Compare(%v) = %d, wanted %d", a64, b64, got, tt.want)
+		}
+		if got := a64.Equal(*b64); got != (tt.want == 0) {
+			t.Errorf("(%v).Equal(%v) = %t, wanted %t", a64, b64, got, tt.want == 0)
+		}
+		if got := EqualBitlists(tt.a, b64); got != (tt.want == 0) {
+			t.Errorf("EqualBitlists(%x, %v) = %t, wanted %t", tt.a, b64, got, tt.want == 0)
+		}
+	}
+}
+
+func TestBitlist_HashAndKey(t *testing.T) {
+	seen := make(map[string]Bitlist)
+	for size := uint64(0); size < 130; size++ {
+		for _, pattern := range []uint64{0, 1, 3, 5} {
+			b := NewBitlist(size)
+			for i := uint64(0); i < size; i++ {
+				if pattern != 0 && i%pattern == 0 {
+					b.SetBitAt(i, true)
+				}
+			}
+			b64, err := b.ToBitlist64()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if b.Hash() != b64.Hash() {
+				t.Errorf("Hash() of %x differs between Bitlist and Bitlist64", b)
+			}
+			if b.Key() != b64.Key() {
+				t.Errorf("Key() of %x differs between Bitlist and Bitlist64", b)
+			}
+			if !EqualBitlists(b, b64) {
+				t.Errorf("EqualBitlists(%x, %v) = false", b, b64)
+			}
+			if other, ok := seen[b.Key()]; ok && !other.Equal(b) {
+				t.Errorf("Key() of %x collides with %x", b, other)
+			}
+			seen[b.Key()] = b
+		}
+	}
+}
+
+func TestBitlist_HashIgnoresUnusedBits(t *testing.T) {
+	b64 := NewBitlist64From([]uint64{0xFF})
+	b64.Truncate(4)
+	if got, want := b64.Hash(), NewBitlist64From([]uint64{0x0F}).Hash(); got == want {
+		t.Fatal("Hash() of different lengths should differ")
+	}
+	clean := NewBitlist64(4)
+	for i := uint64(0); i < 4; i++ {
+		clean.SetBitAt(i, true)
+	}
+	if !b64.Equal(*clean) || b64.Hash() != clean.Hash() || b64.Key() != clean.Key() {
+		t.Errorf("truncated bitlist %v does not match %v", b64, clean)
+	}
+}
+
+func TestBitvector_Compare(t *testing.T) {
+	// Bits above the length of a Bitvector4 are ignored.
+	if a, b := (Bitvector4{0xF3}), (Bitvector4{0x03}); !a.Equal(b) || a.Hash() != b.Hash() || a.Key() != b.Key() {
+		t.Errorf("%x and %x should be equal", a, b)
+	}
+	if got := (Bitvector4{0x02}).Compare(Bitvector4{0x01}); got != -1 {
+		t.Errorf("Compare() = %d, wanted -1", got)
+	}
+
+	a, b := NewBitvector512(), NewBitvector512()
+	a.SetBitAt(300, true)
+	b.SetBitAt(301, true)
+	if got := a.Compare(b); got != 1 {
+		t.Errorf("Compare() = %d, wanted 1", got)
+	}
+	if a.Equal(b) || a.Hash() == b.Hash() || a.Key() == b.Key() {
+		t.Errorf("bitvectors with different bits should not be equal")
+	}
+	b.SetBitAt(301, false)
+	b.SetBitAt(300, true)
+	if !a.Equal(b) || a.Hash() != b.Hash() || a.Key() != b.Key() {
+		t.Errorf("bitvectors with the same bits should be equal")
+	}
+}
+
+func TestBitvector_KeyOfNil(t *testing.T) {
+	type keyer interface {
+		Hash() uint64
+		Key() string
+	}
+	tests := []struct {
+		name      string
+		nil, zero keyer
+		equal     bool
+	}{
+		{"Bitvector4", Bitvector4(nil), NewBitvector4(), Bitvector4(nil).Equal(NewBitvector4())},
+		{"Bitvector8", Bitvector8(nil), NewBitvector8(), Bitvector8(nil).Equal(NewBitvector8())},
+		{"Bitvector32", Bitvector32(nil), NewBitvector32(), Bitvector32(nil).Equal(NewBitvector32())},
+		{"Bitvector64", Bitvector64(nil), NewBitvector64(), Bitvector64(nil).Equal(NewBitvector64())},
+		{"Bitvector128", Bitvector128(nil), NewBitvector128(), Bitvector128(nil).Equal(NewBitvector128())},
+		{"Bitvector256", Bitvector256(nil), NewBitvector256(), Bitvector256(nil).Equal(NewBitvector256())},
+		{"Bitvector512", Bitvector512(nil), NewBitvector512(), Bitvector512(nil).Equal(NewBitvector512())},
+	}
+	for _, tt := range tests {
+		if !tt.equal {
+			t.Errorf("%s: nil and zero bitvectors should be equal", tt.name)
+		}
+		if tt.nil.Hash() != tt.zero.Hash() || tt.nil.Key() != tt.zero.Key() {
+			t.Errorf("%s: nil and zero bitvectors should have the same hash and key", tt.name)
+		}
+	}
+}
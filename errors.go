@@ -1,9 +1,78 @@
 package bitfield
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrBitlistDifferentLength   = errors.New("bitlists are different lengths")
 	ErrBitvectorDifferentLength = errors.New("bitvectors are different lengths")
 	ErrWrongLen                 = errors.New("bitvector is wrong length")
+	ErrBitlistWrongLen          = errors.New("bitlist is wrong length")
+	ErrIndexOutOfRange          = errors.New("index out of range")
+	ErrWeightsWrongLength       = errors.New("weights length does not match bitfield length")
+	ErrWeightOverflow           = errors.New("weighted count overflows uint64")
+	ErrIndicesWrongLength       = errors.New("indices length does not match bitlist length")
+	ErrAggregationBitsLength    = errors.New("aggregation bits length does not match committee sizes")
+	ErrTooManyCommittees        = errors.New("too many committees")
+	ErrBitfieldDifferentLength  = errors.New("bitfields are different lengths")
+	ErrInvalidDiff              = errors.New("invalid bitfield diff encoding")
+	ErrInvalidEncoding          = errors.New("invalid bitfield encoding")
+	ErrChecksumMismatch         = errors.New("bitfield checksum mismatch")
+	ErrBloomFilterParameters    = errors.New("invalid bloom filter parameters")
+	ErrBloomFilterMismatch      = errors.New("bloom filters use a different number of hash functions")
+	ErrReadOnly                 = errors.New("bitfield is read-only")
 )
+
+// LengthMismatchError is returned when an operation is given operands of mismatching lengths.
+// Left and Right are the mismatching lengths: in bits when bitfield lengths are compared, or in
+// bytes when the underlying byte slice of a bitvector is of the wrong size. The Err field holds one
+// of the sentinel errors above, so errors.Is(err, ErrBitlistDifferentLength) and friends keep
+// working.
+type LengthMismatchError struct {
+	Op    string
+	Left  uint64
+	Right uint64
+	Err   error
+}
+
+func (e *LengthMismatchError) Error() string {
+	return fmt.Sprintf("%s: %v (%d != %d)", e.Op, e.Err, e.Left, e.Right)
+}
+
+func (e *LengthMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// IndexOutOfRangeError is returned when an operation accesses the bit at Index of a bitfield
+// holding only Len bits. It matches ErrIndexOutOfRange with errors.Is.
+type IndexOutOfRangeError struct {
+	Op    string
+	Index uint64
+	Len   uint64
+}
+
+func (e *IndexOutOfRangeError) Error() string {
+	return fmt.Sprintf("%s: %v (index %d, length %d)", e.Op, ErrIndexOutOfRange, e.Index, e.Len)
+}
+
+func (e *IndexOutOfRangeError) Unwrap() error {
+	return ErrIndexOutOfRange
+}
+
+// lengthMismatch wraps the sentinel err into a LengthMismatchError of the given operation.
+func lengthMismatch(op string, err error, left, right uint64) error {
+	return &LengthMismatchError{Op: op, Left: left, Right: right, Err: err}
+}
+
+// indexOutOfRange creates an IndexOutOfRangeError of the given operation.
+func indexOutOfRange(op string, idx, size uint64) error {
+	return &IndexOutOfRangeError{Op: op, Index: idx, Len: size}
+}
+
+// invalidRange creates an error of the given operation for a range that starts after its end. It
+// matches ErrIndexOutOfRange with errors.Is.
+func invalidRange(op string, start, end uint64) error {
+	return fmt.Errorf("%s: %w (range start %d is after end %d)", op, ErrIndexOutOfRange, start, end)
+}
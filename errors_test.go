@@ -0,0 +1,115 @@
+package bitfield
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLengthMismatchError(t *testing.T) {
+	_, err := NewBitlist64(64).Or(NewBitlist64(128))
+
+	var lengthErr *LengthMismatchError
+	if !errors.As(err, &lengthErr) {
+		t.Fatalf("Or() error = %v, wanted a *LengthMismatchError", err)
+	}
+	want := LengthMismatchError{Op: "Bitlist64.Or", Left: 64, Right: 128, Err: ErrBitlistDifferentLength}
+	if *lengthErr != want {
+		t.Errorf("Or() error = %+v, wanted %+v", *lengthErr, want)
+	}
+	if !errors.Is(err, ErrBitlistDifferentLength) {
+		t.Errorf("errors.Is(%v, %v) = false", err, ErrBitlistDifferentLength)
+	}
+	if errors.Is(err, ErrBitvectorDifferentLength) {
+		t.Errorf("errors.Is(%v, %v) = true", err, ErrBitvectorDifferentLength)
+	}
+	if got, want := err.Error(), "Bitlist64.Or: bitlists are different lengths (64 != 128)"; got != want {
+		t.Errorf("Error() = %q, wanted %q", got, want)
+	}
+
+	_, err = Bitvector8{0x01, 0x02}.WeightedCount(make([]uint64, 8))
+	if got, want := err.Error(), "Bitvector8.WeightedCount: bitvector is wrong length (2 != 1)"; got != want {
+		t.Errorf("Error() = %q, wanted %q", got, want)
+	}
+	if !errors.Is(err, ErrWrongLen) {
+		t.Errorf("errors.Is(%v, %v) = false", err, ErrWrongLen)
+	}
+}
+
+func TestIndexOutOfRangeError(t *testing.T) {
+	_, err := NewBitlist(10).Slice(2, 11)
+
+	var rangeErr *IndexOutOfRangeError
+	if !errors.As(err, &rangeErr) {
+		t.Fatalf("Slice() error = %v, wanted a *IndexOutOfRangeError", err)
+	}
+	want := IndexOutOfRangeError{Op: "Bitlist.Slice", Index: 11, Len: 10}
+	if *rangeErr != want {
+		t.Errorf("Slice() error = %+v, wanted %+v", *rangeErr, want)
+	}
+	if !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("errors.Is(%v, %v) = false", err, ErrIndexOutOfRange)
+	}
+	if got, want := err.Error(), "Bitlist.Slice: index out of range (index 11, length 10)"; got != want {
+		t.Errorf("Error() = %q, wanted %q", got, want)
+	}
+}
+
+func TestNewBitlist64FromBytes_Error(t *testing.T) {
+	_, err := NewBitlist64FromBytes(17, []byte{0x01, 0x02})
+
+	var lengthErr *LengthMismatchError
+	if !errors.As(err, &lengthErr) {
+		t.Fatalf("NewBitlist64FromBytes() error = %v, wanted a *LengthMismatchError", err)
+	}
+	want := LengthMismatchError{Op: "NewBitlist64FromBytes", Left: 2, Right: 3, Err: ErrBitlistWrongLen}
+	if *lengthErr != want {
+		t.Errorf("NewBitlist64FromBytes() error = %+v, wanted %+v", *lengthErr, want)
+	}
+	if !errors.Is(err, ErrBitlistWrongLen) {
+		t.Errorf("errors.Is(%v, %v) = false", err, ErrBitlistWrongLen)
+	}
+}
+
+func TestInvalidRangeError(t *testing.T) {
+	_, err := NewBitlist(10).Slice(5, 3)
+
+	if !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("errors.Is(%v, %v) = false", err, ErrIndexOutOfRange)
+	}
+	if got, want := err.Error(), "Bitlist.Slice: index out of range (range start 5 is after end 3)"; got != want {
+		t.Errorf("Error() = %q, wanted %q", got, want)
+	}
+}
+
+func TestErrorsCarryOperation(t *testing.T) {
+	b := NewBitlist64(3)
+	b.SetBitAt(0, true)
+	b.SetBitAt(2, true)
+	overflow := []uint64{math.MaxUint64, 1, 1}
+	_, weightedErr := b.WeightedCount(overflow)
+	_, parallelErr := b.ParallelWeightedCount(overflow, 2)
+	_, marshalErr := (&BitfieldDiff{Len: 8, Added: []uint64{3, 3}}).MarshalBinary()
+	_, readErr := (&Bitlist64{}).ReadFromExpected(bytes.NewReader([]byte{0xff, 0xff, 0x01}), 3)
+
+	tests := []struct {
+		err      error
+		sentinel error
+		want     string
+	}{
+		{weightedErr, ErrWeightOverflow, "Bitlist64.WeightedCount: weighted count overflows uint64 at index 2"},
+		{parallelErr, ErrWeightOverflow, "Bitlist64.ParallelWeightedCount: weighted count overflows uint64 at index 2"},
+		{marshalErr, ErrInvalidDiff, "BitfieldDiff.MarshalBinary: invalid bitfield diff encoding: indices are not strictly increasing (3 after 3)"},
+		{(&BitfieldDiff{}).UnmarshalBinary([]byte{0x08, 0x01, 0x08, 0x00}), ErrInvalidDiff, "BitfieldDiff.UnmarshalBinary: invalid bitfield diff encoding: index out of range for 8 bits"},
+		{readErr, ErrBitlistDifferentLength, "Bitlist64.ReadFromExpected: bitlists are different lengths (8 != 3)"},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.sentinel) {
+			t.Errorf("errors.Is(%v, %v) = false", tt.err, tt.sentinel)
+		}
+		if tt.err == nil || tt.err.Error() != tt.want {
+			t.Errorf("Error() = %v, wanted %q", tt.err, tt.want)
+		}
+	}
+}
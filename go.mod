@@ -1,3 +1,3 @@
 module github.com/prysmaticlabs/go-bitfield
 
-go 1.21
+go 1.22
@@ -0,0 +1,439 @@
+package bitfield
+
+import (
+	"fmt"
+	"io"
+)
+
+// The methods in this file implement the custom type method set expected by gogo/protobuf, so that
+// `bytes` fields can be mapped onto any bitfield type with the customtype option. Bitlists are
+// encoded with their length bit, bitvectors as their raw bytes.
+
+// populateMaxBits bounds the length of the bitlists created by NewPopulatedBitlist and
+// NewPopulatedBitlist64.
+const populateMaxBits = 2048
+
+// randyBitfield is the subset of the random source generated by protobuf generators which is used
+// by the NewPopulatedX functions.
+type randyBitfield interface {
+	Intn(n int) int
+}
+
+// Marshal returns the protobuf encoding of the bitlist, which is a copy of its bytes.
+func (b Bitlist) Marshal() ([]byte, error) {
+	ret := make([]byte, len(b))
+	copy(ret, b)
+	return ret, nil
+}
+
+// MarshalTo writes the protobuf encoding of the bitlist to data, and returns the number of bytes
+// written.
+// This method will return an error if data is shorter than Size().
+func (b *Bitlist) MarshalTo(data []byte) (int, error) {
+	if len(data) < len(*b) {
+		return 0, io.ErrShortBuffer
+	}
+	return copy(data, *b), nil
+}
+
+// Unmarshal decodes the protobuf encoding of the bitlist. The data is copied.
+// This method will return an error if the last byte does not hold the length bit.
+func (b *Bitlist) Unmarshal(data []byte) error {
+	if err := checkLengthBit("Bitlist.Unmarshal", data); err != nil {
+		return err
+	}
+	ret := make(Bitlist, len(data))
+	copy(ret, data)
+	*b = ret
+	return nil
+}
+
+// Size returns the size of the protobuf encoding of the bitlist.
+func (b *Bitlist) Size() int {
+	return len(*b)
+}
+
+// NewPopulatedBitlist returns a bitlist of random length, with random bits set.
+func NewPopulatedBitlist(r randyBitfield) *Bitlist {
+	size := uint64(r.Intn(populateMaxBits))
+	b := NewBitlist(size)
+	for i := uint64(0); i < size; i++ {
+		b.SetBitAt(i, r.Intn(2) == 1)
+	}
+	return &b
+}
+
+// Marshal returns the protobuf encoding of the bitlist, which is the same as the one of a Bitlist
+// holding the same bits.
+func (b Bitlist64) Marshal() ([]byte, error) {
+	return appendBitlistBytes(make([]byte, 0, b.Size()), b.size, b.byteAt), nil
+}
+
+// MarshalTo writes the protobuf encoding of the bitlist to data, and returns the number of bytes
+// written.
+// This method will return an error if data is shorter than Size().
+func (b *Bitlist64) MarshalTo(data []byte) (int, error) {
+	n := b.Size()
+	if len(data) < n {
+		return 0, io.ErrShortBuffer
+	}
+	appendBitlistBytes(data[:0], b.size, b.byteAt)
+	return n, nil
+}
+
+// Unmarshal decodes the protobuf encoding of the bitlist.
+// This method will return an error if the last byte does not hold the length bit.
+func (b *Bitlist64) Unmarshal(data []byte) error {
+	if err := checkLengthBit("Bitlist64.Unmarshal", data); err != nil {
+		return err
+	}
+	ret, err := Bitlist(data).ToBitlist64()
+	if err != nil {
+		return err
+	}
+	*b = *ret
+	return nil
+}
+
+// Size returns the size of the protobuf encoding of the bitlist.
+func (b *Bitlist64) Size() int {
+	return int(b.size/8) + 1
+}
+
+// NewPopulatedBitlist64 returns a bitlist of random length, with random bits set.
+func NewPopulatedBitlist64(r randyBitfield) *Bitlist64 {
+	size := uint64(r.Intn(populateMaxBits))
+	b := NewBitlist64(size)
+	for i := uint64(0); i < size; i++ {
+		b.SetBitAt(i, r.Intn(2) == 1)
+	}
+	return b
+}
+
+// checkLengthBit returns an error if data is not empty and its last byte is zero, i.e. it does not
+// hold the length bit of a bitlist. The name of the calling method is used for error reporting.
+func checkLengthBit(name string, data []byte) error {
+	if len(data) > 0 && data[len(data)-1] == 0 {
+		return fmt.Errorf("%s: %w: missing length bit", name, ErrInvalidEncoding)
+	}
+	return nil
+}
+
+// marshalVectorTo writes the bytes of a bitvector to data. An empty bitvector, such as the zero
+// value of a protobuf field, is written as byteSize zero bytes. The name of the calling method is
+// used for error reporting.
+func marshalVectorTo(name string, data, b []byte, byteSize int) (int, error) {
+	if len(b) == 0 {
+		b = make([]byte, byteSize)
+	}
+	if err := checkVectorLen(name, b, byteSize); err != nil {
+		return 0, err
+	}
+	if len(data) < byteSize {
+		return 0, io.ErrShortBuffer
+	}
+	return copy(data, b), nil
+}
+
+// unmarshalVector returns a copy of data, after checking that it holds byteSize bytes. The name of
+// the calling method is used for error reporting.
+func unmarshalVector(name string, data []byte, byteSize int) ([]byte, error) {
+	if len(data) != byteSize {
+		return nil, lengthMismatch(name, ErrWrongLen, uint64(len(data)), uint64(byteSize))
+	}
+	ret := make([]byte, byteSize)
+	copy(ret, data)
+	return ret, nil
+}
+
+// populateVector returns byteSize random bytes, with bits at and above bitSize cleared.
+func populateVector(r randyBitfield, byteSize int, bitSize uint64) []byte {
+	ret := make([]byte, byteSize)
+	for i := range ret {
+		ret[i] = byte(r.Intn(256))
+	}
+	if bitSize%8 != 0 {
+		ret[byteSize-1] &= uint8(1<<(bitSize%8)) - 1
+	}
+	return ret
+}
+
+// Marshal returns the protobuf encoding of the bitvector, which is a copy of its bytes.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector4) Marshal() ([]byte, error) {
+	ret := make([]byte, bitvector4ByteSize)
+	if _, err := marshalVectorTo("Bitvector4.Marshal", ret, b, bitvector4ByteSize); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MarshalTo writes the protobuf encoding of the bitvector to data, and returns the number of bytes
+// written.
+// This method will return an error if the bitvector is of the wrong length, or if data is shorter
+// than Size().
+func (b *Bitvector4) MarshalTo(data []byte) (int, error) {
+	return marshalVectorTo("Bitvector4.MarshalTo", data, *b, bitvector4ByteSize)
+}
+
+// Unmarshal decodes the protobuf encoding of the bitvector. The data is copied.
+// This method will return an error if data is of the wrong length.
+func (b *Bitvector4) Unmarshal(data []byte) error {
+	ret, err := unmarshalVector("Bitvector4.Unmarshal", data, bitvector4ByteSize)
+	if err != nil {
+		return err
+	}
+	*b = ret
+	return nil
+}
+
+// Size returns the size of the protobuf encoding of the bitvector.
+func (b *Bitvector4) Size() int {
+	return bitvector4ByteSize
+}
+
+// NewPopulatedBitvector4 returns a bitvector with random bits set.
+func NewPopulatedBitvector4(r randyBitfield) *Bitvector4 {
+	b := Bitvector4(populateVector(r, bitvector4ByteSize, bitvector4BitSize))
+	return &b
+}
+
+// Marshal returns the protobuf encoding of the bitvector, which is a copy of its bytes.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector8) Marshal() ([]byte, error) {
+	ret := make([]byte, bitvector8ByteSize)
+	if _, err := marshalVectorTo("Bitvector8.Marshal", ret, b, bitvector8ByteSize); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MarshalTo writes the protobuf encoding of the bitvector to data, and returns the number of bytes
+// written.
+// This method will return an error if the bitvector is of the wrong length, or if data is shorter
+// than Size().
+func (b *Bitvector8) MarshalTo(data []byte) (int, error) {
+	return marshalVectorTo("Bitvector8.MarshalTo", data, *b, bitvector8ByteSize)
+}
+
+// Unmarshal decodes the protobuf encoding of the bitvector. The data is copied.
+// This method will return an error if data is of the wrong length.
+func (b *Bitvector8) Unmarshal(data []byte) error {
+	ret, err := unmarshalVector("Bitvector8.Unmarshal", data, bitvector8ByteSize)
+	if err != nil {
+		return err
+	}
+	*b = ret
+	return nil
+}
+
+// Size returns the size of the protobuf encoding of the bitvector.
+func (b *Bitvector8) Size() int {
+	return bitvector8ByteSize
+}
+
+// NewPopulatedBitvector8 returns a bitvector with random bits set.
+func NewPopulatedBitvector8(r randyBitfield) *Bitvector8 {
+	b := Bitvector8(populateVector(r, bitvector8ByteSize, bitvector8BitSize))
+	return &b
+}
+
+// Marshal returns the protobuf encoding of the bitvector, which is a copy of its bytes.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector32) Marshal() ([]byte, error) {
+	ret := make([]byte, bitvector32ByteSize)
+	if _, err := marshalVectorTo("Bitvector32.Marshal", ret, b, bitvector32ByteSize); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MarshalTo writes the protobuf encoding of the bitvector to data, and returns the number of bytes
+// written.
+// This method will return an error if the bitvector is of the wrong length, or if data is shorter
+// than Size().
+func (b *Bitvector32) MarshalTo(data []byte) (int, error) {
+	return marshalVectorTo("Bitvector32.MarshalTo", data, *b, bitvector32ByteSize)
+}
+
+// Unmarshal decodes the protobuf encoding of the bitvector. The data is copied.
+// This method will return an error if data is of the wrong length.
+func (b *Bitvector32) Unmarshal(data []byte) error {
+	ret, err := unmarshalVector("Bitvector32.Unmarshal", data, bitvector32ByteSize)
+	if err != nil {
+		return err
+	}
+	*b = ret
+	return nil
+}
+
+// Size returns the size of the protobuf encoding of the bitvector.
+func (b *Bitvector32) Size() int {
+	return bitvector32ByteSize
+}
+
+// NewPopulatedBitvector32 returns a bitvector with random bits set.
+func NewPopulatedBitvector32(r randyBitfield) *Bitvector32 {
+	b := Bitvector32(populateVector(r, bitvector32ByteSize, bitvector32BitSize))
+	return &b
+}
+
+// Marshal returns the protobuf encoding of the bitvector, which is a copy of its bytes.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector64) Marshal() ([]byte, error) {
+	ret := make([]byte, bitvector64ByteSize)
+	if _, err := marshalVectorTo("Bitvector64.Marshal", ret, b, bitvector64ByteSize); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MarshalTo writes the protobuf encoding of the bitvector to data, and returns the number of bytes
+// written.
+// This method will return an error if the bitvector is of the wrong length, or if data is shorter
+// than Size().
+func (b *Bitvector64) MarshalTo(data []byte) (int, error) {
+	return marshalVectorTo("Bitvector64.MarshalTo", data, *b, bitvector64ByteSize)
+}
+
+// Unmarshal decodes the protobuf encoding of the bitvector. The data is copied.
+// This method will return an error if data is of the wrong length.
+func (b *Bitvector64) Unmarshal(data []byte) error {
+	ret, err := unmarshalVector("Bitvector64.Unmarshal", data, bitvector64ByteSize)
+	if err != nil {
+		return err
+	}
+	*b = ret
+	return nil
+}
+
+// Size returns the size of the protobuf encoding of the bitvector.
+func (b *Bitvector64) Size() int {
+	return bitvector64ByteSize
+}
+
+// NewPopulatedBitvector64 returns a bitvector with random bits set.
+func NewPopulatedBitvector64(r randyBitfield) *Bitvector64 {
+	b := Bitvector64(populateVector(r, bitvector64ByteSize, bitvector64BitSize))
+	return &b
+}
+
+// Marshal returns the protobuf encoding of the bitvector, which is a copy of its bytes.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector128) Marshal() ([]byte, error) {
+	ret := make([]byte, bitvector128ByteSize)
+	if _, err := marshalVectorTo("Bitvector128.Marshal", ret, b, bitvector128ByteSize); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MarshalTo writes the protobuf encoding of the bitvector to data, and returns the number of bytes
+// written.
+// This method will return an error if the bitvector is of the wrong length, or if data is shorter
+// than Size().
+func (b *Bitvector128) MarshalTo(data []byte) (int, error) {
+	return marshalVectorTo("Bitvector128.MarshalTo", data, *b, bitvector128ByteSize)
+}
+
+// Unmarshal decodes the protobuf encoding of the bitvector. The data is copied.
+// This method will return an error if data is of the wrong length.
+func (b *Bitvector128) Unmarshal(data []byte) error {
+	ret, err := unmarshalVector("Bitvector128.Unmarshal", data, bitvector128ByteSize)
+	if err != nil {
+		return err
+	}
+	*b = ret
+	return nil
+}
+
+// Size returns the size of the protobuf encoding of the bitvector.
+func (b *Bitvector128) Size() int {
+	return bitvector128ByteSize
+}
+
+// NewPopulatedBitvector128 returns a bitvector with random bits set.
+func NewPopulatedBitvector128(r randyBitfield) *Bitvector128 {
+	b := Bitvector128(populateVector(r, bitvector128ByteSize, bitvector128BitSize))
+	return &b
+}
+
+// Marshal returns the protobuf encoding of the bitvector, which is a copy of its bytes.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector256) Marshal() ([]byte, error) {
+	ret := make([]byte, bitvector256ByteSize)
+	if _, err := marshalVectorTo("Bitvector256.Marshal", ret, b, bitvector256ByteSize); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MarshalTo writes the protobuf encoding of the bitvector to data, and returns the number of bytes
+// written.
+// This method will return an error if the bitvector is of the wrong length, or if data is shorter
+// than Size().
+func (b *Bitvector256) MarshalTo(data []byte) (int, error) {
+	return marshalVectorTo("Bitvector256.MarshalTo", data, *b, bitvector256ByteSize)
+}
+
+// Unmarshal decodes the protobuf encoding of the bitvector. The data is copied.
+// This method will return an error if data is of the wrong length.
+func (b *Bitvector256) Unmarshal(data []byte) error {
+	ret, err := unmarshalVector("Bitvector256.Unmarshal", data, bitvector256ByteSize)
+	if err != nil {
+		return err
+	}
+	*b = ret
+	return nil
+}
+
+// Size returns the size of the protobuf encoding of the bitvector.
+func (b *Bitvector256) Size() int {
+	return bitvector256ByteSize
+}
+
+// NewPopulatedBitvector256 returns a bitvector with random bits set.
+func NewPopulatedBitvector256(r randyBitfield) *Bitvector256 {
+	b := Bitvector256(populateVector(r, bitvector256ByteSize, bitvector256BitSize))
+	return &b
+}
+
+// Marshal returns the protobuf encoding of the bitvector, which is a copy of its bytes.
+// This method will return an error if the bitvector is of the wrong length.
+func (b Bitvector512) Marshal() ([]byte, error) {
+	ret := make([]byte, bitvector512ByteSize)
+	if _, err := marshalVectorTo("Bitvector512.Marshal", ret, b, bitvector512ByteSize); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MarshalTo writes the protobuf encoding of the bitvector to data, and returns the number of bytes
+// written.
+// This method will return an error if the bitvector is of the wrong length, or if data is shorter
+// than Size().
+func (b *Bitvector512) MarshalTo(data []byte) (int, error) {
+	return marshalVectorTo("Bitvector512.MarshalTo", data, *b, bitvector512ByteSize)
+}
+
+// Unmarshal decodes the protobuf encoding of the bitvector. The data is copied.
+// This method will return an error if data is of the wrong length.
+func (b *Bitvector512) Unmarshal(data []byte) error {
+	ret, err := unmarshalVector("Bitvector512.Unmarshal", data, bitvector512ByteSize)
+	if err != nil {
+		return err
+	}
+	*b = ret
+	return nil
+}
+
+// Size returns the size of the protobuf encoding of the bitvector.
+func (b *Bitvector512) Size() int {
+	return bitvector512ByteSize
+}
+
+// NewPopulatedBitvector512 returns a bitvector with random bits set.
+func NewPopulatedBitvector512(r randyBitfield) *Bitvector512 {
+	b := Bitvector512(populateVector(r, bitvector512ByteSize, bitvector512BitSize))
+	return &b
+}
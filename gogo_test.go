@@ -0,0 +1,51 @@
+package bitfield
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// Round trips through a message generated by protoc-gen-gogo are tested in internal/gogotest.
+
+func TestGogoCustomType_Bitlist64MatchesBitlist(t *testing.T) {
+	b := NewBitlist64(13)
+	b.SetBitAt(0, true)
+	b.SetBitAt(12, true)
+	data, err := b.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := b.ToBitlist(); !reflect.DeepEqual(data, []byte(want)) {
+		t.Errorf("Marshal() = %x, wanted %x", data, want)
+	}
+}
+
+func TestGogoCustomType_Errors(t *testing.T) {
+	if err := (&Bitlist{}).Unmarshal([]byte{0x01, 0x00}); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("Bitlist.Unmarshal() error = %v, wanted %v", err, ErrInvalidEncoding)
+	}
+	if err := (&Bitlist64{}).Unmarshal([]byte{0x00}); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("Bitlist64.Unmarshal() error = %v, wanted %v", err, ErrInvalidEncoding)
+	}
+	if err := (&Bitvector32{}).Unmarshal([]byte{0x01}); !errors.Is(err, ErrWrongLen) {
+		t.Errorf("Bitvector32.Unmarshal() error = %v, wanted %v", err, ErrWrongLen)
+	}
+	if _, err := (Bitvector32{0x01}).Marshal(); !errors.Is(err, ErrWrongLen) {
+		t.Errorf("Bitvector32.Marshal() error = %v, wanted %v", err, ErrWrongLen)
+	}
+
+	b := Bitlist{0x01, 0x02}
+	if _, err := b.MarshalTo(make([]byte, 1)); !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("Bitlist.MarshalTo() error = %v, wanted %v", err, io.ErrShortBuffer)
+	}
+	b64 := NewBitlist64(9)
+	if _, err := b64.MarshalTo(make([]byte, 1)); !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("Bitlist64.MarshalTo() error = %v, wanted %v", err, io.ErrShortBuffer)
+	}
+	v := NewBitvector8()
+	if _, err := v.MarshalTo(nil); !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("Bitvector8.MarshalTo() error = %v, wanted %v", err, io.ErrShortBuffer)
+	}
+}
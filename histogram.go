@@ -0,0 +1,80 @@
+package bitfield
+
+import (
+	"math/bits"
+)
+
+// Bitlist64Histogram returns, for every bit position, the number of bitlists that have the bit at
+// that position set. Bits are tallied one word column at a time with bit-sliced counters, which
+// keeps the work per input word constant.
+// This method will return an error if the bitlists are not the same length.
+func Bitlist64Histogram(srcs ...*Bitlist64) ([]uint32, error) {
+	if len(srcs) == 0 {
+		return []uint32{}, nil
+	}
+	size := srcs[0].Len()
+	for _, src := range srcs[1:] {
+		if src.Len() != size {
+			return nil, lengthMismatch("Bitlist64Histogram", ErrBitlistDifferentLength, size, src.Len())
+		}
+	}
+
+	counts := make([]uint32, size)
+	counter := newSlicedCounter[uint64](len(srcs))
+	for idx := range srcs[0].data {
+		counter.reset()
+		for _, src := range srcs {
+			counter.add(src.data[idx])
+		}
+		start := uint64(idx) << wordSizeLog2
+		counter.addTo(counts[start:min(len(counts), int(start+wordSize))])
+	}
+
+	return counts, nil
+}
+
+// BitlistHistogram returns, for every bit position, the number of bitlists that have the bit at
+// that position set. Bits are tallied one byte column at a time with bit-sliced counters, which
+// keeps the work per input byte constant.
+// This method will return an error if the bitlists are not the same length.
+func BitlistHistogram(srcs ...Bitlist) ([]uint32, error) {
+	if len(srcs) == 0 {
+		return []uint32{}, nil
+	}
+	size := srcs[0].Len()
+	for _, src := range srcs[1:] {
+		if src.Len() != size || len(src) != len(srcs[0]) {
+			return nil, lengthMismatch("BitlistHistogram", ErrBitlistDifferentLength, size, src.Len())
+		}
+	}
+
+	counts := make([]uint32, size)
+	counter := newSlicedCounter[uint8](len(srcs))
+	for idx := range srcs[0] {
+		counter.reset()
+		for _, src := range srcs {
+			counter.add(src[idx])
+		}
+		// The length bit of the last byte falls beyond the end of counts, so it is dropped.
+		start := idx * 8
+		counter.addTo(counts[min(len(counts), start):min(len(counts), start+8)])
+	}
+
+	return counts, nil
+}
+
+// addTo adds the counter value of every lane to counts, where counts[i] corresponds to lane i.
+// Lanes beyond the length of counts are ignored.
+func (c *slicedCounter[T]) addTo(counts []uint32) {
+	for p, plane := range c.planes {
+		for plane != 0 {
+			lane := bits.TrailingZeros64(uint64(plane))
+			if lane >= len(counts) {
+				break
+			}
+			counts[lane] += 1 << uint(p)
+			// Clear the lowest set bit.
+			plane &= plane - 1
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package bitfield
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestBitlist64Histogram(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []uint64{0, 1, 63, 64, 65, 300} {
+		for _, n := range []int{1, 2, 3, 15, 16, 100} {
+			t.Run(fmt.Sprintf("size:%d,n:%d", size, n), func(t *testing.T) {
+				srcs := randomBitlists64(r, n, size)
+				bitlists := make([]Bitlist, n)
+				generic := make([]Bitfield, n)
+				for i, src := range srcs {
+					bitlists[i] = src.ToBitlist()
+					generic[i] = src
+				}
+				want := make([]uint32, size)
+				for i := range want {
+					want[i] = uint32(votes(generic, uint64(i)))
+				}
+
+				got, err := Bitlist64Histogram(srcs...)
+				if err != nil || !reflect.DeepEqual(got, want) {
+					t.Errorf("Bitlist64Histogram() = %v, %v, wanted %v", got, err, want)
+				}
+				got, err = BitlistHistogram(bitlists...)
+				if err != nil || !reflect.DeepEqual(got, want) {
+					t.Errorf("BitlistHistogram() = %v, %v, wanted %v", got, err, want)
+				}
+			})
+		}
+	}
+
+	t.Run("no inputs", func(t *testing.T) {
+		if got, err := Bitlist64Histogram(); len(got) != 0 || err != nil {
+			t.Errorf("Bitlist64Histogram() = %v, %v, wanted empty", got, err)
+		}
+		if got, err := BitlistHistogram(); len(got) != 0 || err != nil {
+			t.Errorf("BitlistHistogram() = %v, %v, wanted empty", got, err)
+		}
+	})
+
+	t.Run("check errors", func(t *testing.T) {
+		if _, err := Bitlist64Histogram(NewBitlist64(64), NewBitlist64(65)); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+		if _, err := BitlistHistogram(NewBitlist(8), NewBitlist(7)); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+	})
+}
@@ -0,0 +1,1063 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: bitfields.proto
+
+package gogotest
+
+import (
+	bytes "bytes"
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	github_com_prysmaticlabs_go_bitfield "github.com/prysmaticlabs/go-bitfield"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// Bitfields holds a field of every bitfield type, to check them against the code generated by
+// protoc-gen-gogo for custom types.
+type Bitfields struct {
+	Bitlist              github_com_prysmaticlabs_go_bitfield.Bitlist       `protobuf:"bytes,1,opt,name=bitlist,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitlist" json:"bitlist"`
+	Bitlist64            github_com_prysmaticlabs_go_bitfield.Bitlist64     `protobuf:"bytes,2,opt,name=bitlist64,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitlist64" json:"bitlist64"`
+	Bitvector4           github_com_prysmaticlabs_go_bitfield.Bitvector4    `protobuf:"bytes,3,opt,name=bitvector4,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitvector4" json:"bitvector4"`
+	Bitvector8           github_com_prysmaticlabs_go_bitfield.Bitvector8    `protobuf:"bytes,4,opt,name=bitvector8,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitvector8" json:"bitvector8"`
+	Bitvector32          github_com_prysmaticlabs_go_bitfield.Bitvector32   `protobuf:"bytes,5,opt,name=bitvector32,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitvector32" json:"bitvector32"`
+	Bitvector64          github_com_prysmaticlabs_go_bitfield.Bitvector64   `protobuf:"bytes,6,opt,name=bitvector64,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitvector64" json:"bitvector64"`
+	Bitvector128         github_com_prysmaticlabs_go_bitfield.Bitvector128  `protobuf:"bytes,7,opt,name=bitvector128,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitvector128" json:"bitvector128"`
+	Bitvector256         github_com_prysmaticlabs_go_bitfield.Bitvector256  `protobuf:"bytes,8,opt,name=bitvector256,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitvector256" json:"bitvector256"`
+	Bitvector512         github_com_prysmaticlabs_go_bitfield.Bitvector512  `protobuf:"bytes,9,opt,name=bitvector512,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitvector512" json:"bitvector512"`
+	OptionalBitlist64    *github_com_prysmaticlabs_go_bitfield.Bitlist64    `protobuf:"bytes,10,opt,name=optional_bitlist64,json=optionalBitlist64,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitlist64" json:"optional_bitlist64,omitempty"`
+	Subnets              []github_com_prysmaticlabs_go_bitfield.Bitvector64 `protobuf:"bytes,11,rep,name=subnets,proto3,customtype=github.com/prysmaticlabs/go-bitfield.Bitvector64" json:"subnets"`
+	XXX_NoUnkeyedLiteral struct{}                                           `json:"-"`
+	XXX_unrecognized     []byte                                             `json:"-"`
+	XXX_sizecache        int32                                              `json:"-"`
+}
+
+func (m *Bitfields) Reset()         { *m = Bitfields{} }
+func (m *Bitfields) String() string { return proto.CompactTextString(m) }
+func (*Bitfields) ProtoMessage()    {}
+func (*Bitfields) Descriptor() ([]byte, []int) {
+	return fileDescriptor_afd95ed737a732b7, []int{0}
+}
+func (m *Bitfields) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Bitfields) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Bitfields.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Bitfields) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Bitfields.Merge(m, src)
+}
+func (m *Bitfields) XXX_Size() int {
+	return m.Size()
+}
+func (m *Bitfields) XXX_DiscardUnknown() {
+	xxx_messageInfo_Bitfields.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Bitfields proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*Bitfields)(nil), "gogotest.Bitfields")
+}
+
+func init() { proto.RegisterFile("bitfields.proto", fileDescriptor_afd95ed737a732b7) }
+
+var fileDescriptor_afd95ed737a732b7 = []byte{
+	// 378 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0xd4, 0x31, 0x6f, 0xea, 0x30,
+	0x10, 0x00, 0x60, 0xfc, 0x78, 0x8f, 0x80, 0x41, 0x7a, 0x7a, 0xd6, 0x1b, 0xac, 0x0e, 0x01, 0x75,
+	0xea, 0xd0, 0x26, 0x8d, 0x49, 0xdc, 0x74, 0xcd, 0x0f, 0xe8, 0x60, 0x21, 0x55, 0x42, 0xaa, 0xaa,
+	0x84, 0xa6, 0xa9, 0xa5, 0x10, 0xa3, 0xc4, 0x54, 0xea, 0x3f, 0xe2, 0xa7, 0x30, 0x76, 0xee, 0x80,
+	0x4a, 0xfa, 0x03, 0xba, 0x76, 0xac, 0x08, 0x09, 0x24, 0x1b, 0x34, 0xdb, 0xdd, 0x59, 0xf7, 0xdd,
+	0xe0, 0xd3, 0xc1, 0xbf, 0x1e, 0x97, 0x8f, 0xdc, 0x0f, 0x1f, 0x12, 0x6d, 0x16, 0x0b, 0x29, 0x50,
+	0x3b, 0x10, 0x81, 0x90, 0x7e, 0x22, 0x4f, 0xfe, 0x6f, 0xa2, 0xac, 0xa8, 0x6f, 0xa2, 0xed, 0xfb,
+	0xe9, 0xa7, 0x02, 0x3b, 0x4e, 0xd1, 0x83, 0x6e, 0xa0, 0xe2, 0x71, 0x19, 0xf2, 0x44, 0x62, 0x30,
+	0x00, 0x67, 0x3d, 0xc7, 0x5c, 0xae, 0xfa, 0x8d, 0xb7, 0x55, 0xff, 0x3c, 0xe0, 0xf2, 0x69, 0xee,
+	0x69, 0x13, 0x31, 0xd5, 0x67, 0xf1, 0x4b, 0x32, 0x75, 0x25, 0x9f, 0x84, 0xae, 0x97, 0xe8, 0x81,
+	0xb8, 0x28, 0x66, 0x6a, 0xce, 0xb6, 0x97, 0x15, 0x08, 0x1a, 0xc1, 0x4e, 0x1e, 0x52, 0x13, 0xff,
+	0xca, 0x44, 0x9a, 0x8b, 0xda, 0x31, 0x22, 0x35, 0xd9, 0x1e, 0x42, 0xb7, 0x10, 0x7a, 0x5c, 0x3e,
+	0xfb, 0x13, 0x29, 0x62, 0x13, 0x37, 0x33, 0xf6, 0x2a, 0x67, 0xf5, 0x43, 0xd9, 0xbc, 0x9d, 0x95,
+	0xa8, 0x0a, 0x6c, 0xe3, 0xdf, 0x75, 0x60, 0xbb, 0x04, 0xdb, 0x68, 0x0c, 0xbb, 0xbb, 0x6c, 0x48,
+	0xf0, 0x9f, 0x4c, 0xb6, 0x73, 0xf9, 0xf2, 0x38, 0x79, 0x48, 0x58, 0x19, 0xab, 0xd8, 0xd4, 0xc4,
+	0xad, 0x3a, 0x36, 0x35, 0x59, 0x19, 0x43, 0x77, 0xb0, 0xb7, 0x4b, 0x0d, 0x62, 0x63, 0x25, 0xc3,
+	0xaf, 0x73, 0xdc, 0x38, 0x0e, 0x37, 0x88, 0xcd, 0x2a, 0x5c, 0x85, 0x27, 0x16, 0xc5, 0xed, 0x3a,
+	0x3c, 0xb1, 0x28, 0xab, 0x70, 0x15, 0xde, 0x32, 0x08, 0xee, 0xd4, 0xe1, 0x2d, 0x83, 0xb0, 0x0a,
+	0x87, 0x5c, 0x88, 0xc4, 0x4c, 0x72, 0x11, 0xb9, 0xe1, 0xfd, 0x7e, 0xcb, 0x61, 0x36, 0x84, 0xfc,
+	0x60, 0xc3, 0xff, 0x15, 0xda, 0xae, 0x84, 0x18, 0x54, 0x92, 0xb9, 0x17, 0xf9, 0x32, 0xc1, 0xdd,
+	0x41, 0xb3, 0xd6, 0xbf, 0x16, 0x90, 0x33, 0x5a, 0xae, 0xd5, 0xc6, 0xd7, 0x5a, 0x05, 0x8b, 0x54,
+	0x05, 0xcb, 0x54, 0x05, 0xaf, 0xa9, 0x0a, 0xde, 0x53, 0x15, 0x2c, 0x3e, 0x54, 0x30, 0xa6, 0x87,
+	0xc0, 0x3a, 0x8f, 0xa4, 0x1f, 0x47, 0x6e, 0xa8, 0x17, 0xd7, 0xc5, 0x6b, 0x65, 0xe7, 0x64, 0xf8,
+	0x3d, 0x00, 0x41, 0xaf, 0xaa, 0x32, 0x81, 0x04, 0x00, 0x00,
+}
+
+func (this *Bitfields) Compare(that interface{}) int {
+	if that == nil {
+		if this == nil {
+			return 0
+		}
+		return 1
+	}
+
+	that1, ok := that.(*Bitfields)
+	if !ok {
+		that2, ok := that.(Bitfields)
+		if ok {
+			that1 = &that2
+		} else {
+			return 1
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return 0
+		}
+		return 1
+	} else if this == nil {
+		return -1
+	}
+	if c := this.Bitlist.Compare(that1.Bitlist); c != 0 {
+		return c
+	}
+	if c := this.Bitlist64.Compare(that1.Bitlist64); c != 0 {
+		return c
+	}
+	if c := this.Bitvector4.Compare(that1.Bitvector4); c != 0 {
+		return c
+	}
+	if c := this.Bitvector8.Compare(that1.Bitvector8); c != 0 {
+		return c
+	}
+	if c := this.Bitvector32.Compare(that1.Bitvector32); c != 0 {
+		return c
+	}
+	if c := this.Bitvector64.Compare(that1.Bitvector64); c != 0 {
+		return c
+	}
+	if c := this.Bitvector128.Compare(that1.Bitvector128); c != 0 {
+		return c
+	}
+	if c := this.Bitvector256.Compare(that1.Bitvector256); c != 0 {
+		return c
+	}
+	if c := this.Bitvector512.Compare(that1.Bitvector512); c != 0 {
+		return c
+	}
+	if that1.OptionalBitlist64 == nil {
+		if this.OptionalBitlist64 != nil {
+			return 1
+		}
+	} else if this.OptionalBitlist64 == nil {
+		return -1
+	} else if c := this.OptionalBitlist64.Compare(*that1.OptionalBitlist64); c != 0 {
+		return c
+	}
+	if len(this.Subnets) != len(that1.Subnets) {
+		if len(this.Subnets) < len(that1.Subnets) {
+			return -1
+		}
+		return 1
+	}
+	for i := range this.Subnets {
+		if c := this.Subnets[i].Compare(that1.Subnets[i]); c != 0 {
+			return c
+		}
+	}
+	if c := bytes.Compare(this.XXX_unrecognized, that1.XXX_unrecognized); c != 0 {
+		return c
+	}
+	return 0
+}
+func (this *Bitfields) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Bitfields)
+	if !ok {
+		that2, ok := that.(Bitfields)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Bitlist.Equal(that1.Bitlist) {
+		return false
+	}
+	if !this.Bitlist64.Equal(that1.Bitlist64) {
+		return false
+	}
+	if !this.Bitvector4.Equal(that1.Bitvector4) {
+		return false
+	}
+	if !this.Bitvector8.Equal(that1.Bitvector8) {
+		return false
+	}
+	if !this.Bitvector32.Equal(that1.Bitvector32) {
+		return false
+	}
+	if !this.Bitvector64.Equal(that1.Bitvector64) {
+		return false
+	}
+	if !this.Bitvector128.Equal(that1.Bitvector128) {
+		return false
+	}
+	if !this.Bitvector256.Equal(that1.Bitvector256) {
+		return false
+	}
+	if !this.Bitvector512.Equal(that1.Bitvector512) {
+		return false
+	}
+	if that1.OptionalBitlist64 == nil {
+		if this.OptionalBitlist64 != nil {
+			return false
+		}
+	} else if !this.OptionalBitlist64.Equal(*that1.OptionalBitlist64) {
+		return false
+	}
+	if len(this.Subnets) != len(that1.Subnets) {
+		return false
+	}
+	for i := range this.Subnets {
+		if !this.Subnets[i].Equal(that1.Subnets[i]) {
+			return false
+		}
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (m *Bitfields) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Bitfields) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Bitfields) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Subnets) > 0 {
+		for iNdEx := len(m.Subnets) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Subnets[iNdEx].Size()
+				i -= size
+				if _, err := m.Subnets[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintBitfields(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if m.OptionalBitlist64 != nil {
+		{
+			size := m.OptionalBitlist64.Size()
+			i -= size
+			if _, err := m.OptionalBitlist64.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintBitfields(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	{
+		size := m.Bitvector512.Size()
+		i -= size
+		if _, err := m.Bitvector512.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintBitfields(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x4a
+	{
+		size := m.Bitvector256.Size()
+		i -= size
+		if _, err := m.Bitvector256.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintBitfields(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x42
+	{
+		size := m.Bitvector128.Size()
+		i -= size
+		if _, err := m.Bitvector128.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintBitfields(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x3a
+	{
+		size := m.Bitvector64.Size()
+		i -= size
+		if _, err := m.Bitvector64.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintBitfields(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x32
+	{
+		size := m.Bitvector32.Size()
+		i -= size
+		if _, err := m.Bitvector32.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintBitfields(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x2a
+	{
+		size := m.Bitvector8.Size()
+		i -= size
+		if _, err := m.Bitvector8.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintBitfields(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
+	{
+		size := m.Bitvector4.Size()
+		i -= size
+		if _, err := m.Bitvector4.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintBitfields(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	{
+		size := m.Bitlist64.Size()
+		i -= size
+		if _, err := m.Bitlist64.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintBitfields(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	{
+		size := m.Bitlist.Size()
+		i -= size
+		if _, err := m.Bitlist.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintBitfields(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintBitfields(dAtA []byte, offset int, v uint64) int {
+	offset -= sovBitfields(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func NewPopulatedBitfields(r randyBitfields, easy bool) *Bitfields {
+	this := &Bitfields{}
+	v1 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitlist(r)
+	this.Bitlist = *v1
+	v2 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitlist64(r)
+	this.Bitlist64 = *v2
+	v3 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitvector4(r)
+	this.Bitvector4 = *v3
+	v4 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitvector8(r)
+	this.Bitvector8 = *v4
+	v5 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitvector32(r)
+	this.Bitvector32 = *v5
+	v6 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitvector64(r)
+	this.Bitvector64 = *v6
+	v7 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitvector128(r)
+	this.Bitvector128 = *v7
+	v8 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitvector256(r)
+	this.Bitvector256 = *v8
+	v9 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitvector512(r)
+	this.Bitvector512 = *v9
+	this.OptionalBitlist64 = github_com_prysmaticlabs_go_bitfield.NewPopulatedBitlist64(r)
+	v10 := r.Intn(10)
+	this.Subnets = make([]github_com_prysmaticlabs_go_bitfield.Bitvector64, v10)
+	for i := 0; i < v10; i++ {
+		v11 := github_com_prysmaticlabs_go_bitfield.NewPopulatedBitvector64(r)
+		this.Subnets[i] = *v11
+	}
+	if !easy && r.Intn(10) != 0 {
+		this.XXX_unrecognized = randUnrecognizedBitfields(r, 12)
+	}
+	return this
+}
+
+type randyBitfields interface {
+	Float32() float32
+	Float64() float64
+	Int63() int64
+	Int31() int32
+	Uint32() uint32
+	Intn(n int) int
+}
+
+func randUTF8RuneBitfields(r randyBitfields) rune {
+	ru := r.Intn(62)
+	if ru < 10 {
+		return rune(ru + 48)
+	} else if ru < 36 {
+		return rune(ru + 55)
+	}
+	return rune(ru + 61)
+}
+func randStringBitfields(r randyBitfields) string {
+	v12 := r.Intn(100)
+	tmps := make([]rune, v12)
+	for i := 0; i < v12; i++ {
+		tmps[i] = randUTF8RuneBitfields(r)
+	}
+	return string(tmps)
+}
+func randUnrecognizedBitfields(r randyBitfields, maxFieldNumber int) (dAtA []byte) {
+	l := r.Intn(5)
+	for i := 0; i < l; i++ {
+		wire := r.Intn(4)
+		if wire == 3 {
+			wire = 5
+		}
+		fieldNumber := maxFieldNumber + r.Intn(100)
+		dAtA = randFieldBitfields(dAtA, r, fieldNumber, wire)
+	}
+	return dAtA
+}
+func randFieldBitfields(dAtA []byte, r randyBitfields, fieldNumber int, wire int) []byte {
+	key := uint32(fieldNumber)<<3 | uint32(wire)
+	switch wire {
+	case 0:
+		dAtA = encodeVarintPopulateBitfields(dAtA, uint64(key))
+		v13 := r.Int63()
+		if r.Intn(2) == 0 {
+			v13 *= -1
+		}
+		dAtA = encodeVarintPopulateBitfields(dAtA, uint64(v13))
+	case 1:
+		dAtA = encodeVarintPopulateBitfields(dAtA, uint64(key))
+		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
+	case 2:
+		dAtA = encodeVarintPopulateBitfields(dAtA, uint64(key))
+		ll := r.Intn(100)
+		dAtA = encodeVarintPopulateBitfields(dAtA, uint64(ll))
+		for j := 0; j < ll; j++ {
+			dAtA = append(dAtA, byte(r.Intn(256)))
+		}
+	default:
+		dAtA = encodeVarintPopulateBitfields(dAtA, uint64(key))
+		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
+	}
+	return dAtA
+}
+func encodeVarintPopulateBitfields(dAtA []byte, v uint64) []byte {
+	for v >= 1<<7 {
+		dAtA = append(dAtA, uint8(uint64(v)&0x7f|0x80))
+		v >>= 7
+	}
+	dAtA = append(dAtA, uint8(v))
+	return dAtA
+}
+func (m *Bitfields) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Bitlist.Size()
+	n += 1 + l + sovBitfields(uint64(l))
+	l = m.Bitlist64.Size()
+	n += 1 + l + sovBitfields(uint64(l))
+	l = m.Bitvector4.Size()
+	n += 1 + l + sovBitfields(uint64(l))
+	l = m.Bitvector8.Size()
+	n += 1 + l + sovBitfields(uint64(l))
+	l = m.Bitvector32.Size()
+	n += 1 + l + sovBitfields(uint64(l))
+	l = m.Bitvector64.Size()
+	n += 1 + l + sovBitfields(uint64(l))
+	l = m.Bitvector128.Size()
+	n += 1 + l + sovBitfields(uint64(l))
+	l = m.Bitvector256.Size()
+	n += 1 + l + sovBitfields(uint64(l))
+	l = m.Bitvector512.Size()
+	n += 1 + l + sovBitfields(uint64(l))
+	if m.OptionalBitlist64 != nil {
+		l = m.OptionalBitlist64.Size()
+		n += 1 + l + sovBitfields(uint64(l))
+	}
+	if len(m.Subnets) > 0 {
+		for _, e := range m.Subnets {
+			l = e.Size()
+			n += 1 + l + sovBitfields(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovBitfields(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozBitfields(x uint64) (n int) {
+	return sovBitfields(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Bitfields) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBitfields
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Bitfields: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Bitfields: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bitlist", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Bitlist.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bitlist64", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Bitlist64.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bitvector4", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Bitvector4.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bitvector8", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Bitvector8.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bitvector32", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Bitvector32.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bitvector64", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Bitvector64.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bitvector128", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Bitvector128.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bitvector256", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Bitvector256.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bitvector512", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Bitvector512.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OptionalBitlist64", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_prysmaticlabs_go_bitfield.Bitlist64
+			m.OptionalBitlist64 = &v
+			if err := m.OptionalBitlist64.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subnets", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_prysmaticlabs_go_bitfield.Bitvector64
+			m.Subnets = append(m.Subnets, v)
+			if err := m.Subnets[len(m.Subnets)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBitfields(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthBitfields
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipBitfields(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowBitfields
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowBitfields
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthBitfields
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupBitfields
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthBitfields
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthBitfields        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowBitfields          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupBitfields = fmt.Errorf("proto: unexpected end of group")
+)
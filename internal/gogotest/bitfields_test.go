@@ -0,0 +1,114 @@
+package gogotest
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	bitfield "github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestBitfields_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		m := NewPopulatedBitfields(r, i%2 == 0)
+		data, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != m.Size() {
+			t.Errorf("len(Marshal()) = %d, wanted Size() = %d", len(data), m.Size())
+		}
+
+		got := &Bitfields{}
+		if err := proto.Unmarshal(data, got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(m) || got.Compare(m) != 0 {
+			t.Fatalf("round trip of %v returned %v", m, got)
+		}
+		// The decoded message must not alias the encoding buffer.
+		for i := range data {
+			data[i] = 0
+		}
+		if !got.Equal(m) {
+			t.Fatal("decoded message aliases the encoding buffer")
+		}
+	}
+}
+
+func TestBitfields_WireFormat(t *testing.T) {
+	b64 := bitfield.NewBitlist64(13)
+	b64.SetBitAt(0, true)
+	b64.SetBitAt(12, true)
+	m := &Bitfields{
+		Bitlist:           bitfield.Bitlist{0x05},
+		Bitlist64:         *b64,
+		Bitvector4:        bitfield.Bitvector4{0x03},
+		Bitvector8:        bitfield.NewBitvector8(),
+		Bitvector32:       bitfield.NewBitvector32(),
+		Bitvector64:       bitfield.NewBitvector64(),
+		Bitvector128:      bitfield.NewBitvector128(),
+		Bitvector256:      bitfield.NewBitvector256(),
+		Bitvector512:      bitfield.NewBitvector512(),
+		OptionalBitlist64: b64,
+		Subnets:           []bitfield.Bitvector64{{0x01, 0, 0, 0, 0, 0, 0, 0x80}},
+	}
+	data, err := m.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every field holds the SSZ bytes of the bitfield, as a length-delimited field.
+	want := map[uint64][]byte{
+		1:  {0x05},
+		2:  b64.ToBitlist(),
+		3:  {0x03},
+		4:  make([]byte, 1),
+		5:  make([]byte, 4),
+		6:  make([]byte, 8),
+		7:  make([]byte, 16),
+		8:  make([]byte, 32),
+		9:  make([]byte, 64),
+		10: b64.ToBitlist(),
+		11: {0x01, 0, 0, 0, 0, 0, 0, 0x80},
+	}
+	buf := proto.NewBuffer(data)
+	for field := uint64(1); field <= 11; field++ {
+		key, err := buf.DecodeVarint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != field<<3|proto.WireBytes {
+			t.Fatalf("key = %#x, wanted field %d with wire type %d", key, field, proto.WireBytes)
+		}
+		value, err := buf.DecodeRawBytes(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(value, want[field]) {
+			t.Errorf("field %d = %#x, wanted %#x", field, value, want[field])
+		}
+	}
+}
+
+func TestBitfields_ZeroValue(t *testing.T) {
+	data, err := (&Bitfields{}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &Bitfields{}
+	if err := got.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bitvector64, bitfield.NewBitvector64()) {
+		t.Errorf("zero value Bitvector64 decoded as %#x", got.Bitvector64)
+	}
+	if got.Bitlist64.Len() != 0 {
+		t.Errorf("zero value Bitlist64 decoded with length %d", got.Bitlist64.Len())
+	}
+	if got.OptionalBitlist64 != nil {
+		t.Errorf("unset OptionalBitlist64 decoded as %v", got.OptionalBitlist64)
+	}
+}
@@ -0,0 +1,10 @@
+// Package gogotest holds a message generated by protoc-gen-gogo with a field of every bitfield type,
+// to test the bitfield types against the code gogo/protobuf generates for custom types.
+//
+// It is a separate module, so that gogo/protobuf is not a dependency of the bitfield module.
+//
+// To regenerate bitfields.pb.go, run from this directory:
+//
+//	GOGO=$(go list -m -f '{{.Dir}}' github.com/gogo/protobuf)
+//	protoc -I. -I$GOGO -I$GOGO/protobuf --gogo_out=paths=source_relative:. bitfields.proto
+package gogotest
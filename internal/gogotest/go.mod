@@ -0,0 +1,10 @@
+module github.com/prysmaticlabs/go-bitfield/internal/gogotest
+
+go 1.22
+
+require (
+	github.com/gogo/protobuf v1.3.2
+	github.com/prysmaticlabs/go-bitfield v0.0.0
+)
+
+replace github.com/prysmaticlabs/go-bitfield => ../..
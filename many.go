@@ -0,0 +1,203 @@
+package bitfield
+
+import (
+	"math/bits"
+)
+
+// OrMany sets the bitlist to the union (OR) of all the provided bitlists. The receiver is
+// overwritten, so no allocation takes place, and it is safe to pass it as one of the inputs.
+// With no inputs, all bits are cleared.
+// This method will return an error if the bitlists are not the same length.
+func (b *Bitlist64) OrMany(srcs ...*Bitlist64) error {
+	if err := b.checkManyLen("Bitlist64.OrMany", srcs); err != nil {
+		return err
+	}
+
+	for idx := range b.data {
+		word := uint64(0)
+		for _, src := range srcs {
+			word |= src.data[idx]
+		}
+		b.data[idx] = word
+	}
+	return nil
+}
+
+// AndMany sets the bitlist to the intersection (AND) of all the provided bitlists. The receiver is
+// overwritten, so no allocation takes place, and it is safe to pass it as one of the inputs.
+// With no inputs, all bits are set.
+// This method will return an error if the bitlists are not the same length.
+func (b *Bitlist64) AndMany(srcs ...*Bitlist64) error {
+	if err := b.checkManyLen("Bitlist64.AndMany", srcs); err != nil {
+		return err
+	}
+
+	for idx := range b.data {
+		word := allBitsSet
+		for _, src := range srcs {
+			word &= src.data[idx]
+		}
+		b.data[idx] = word
+	}
+	b.clearUnusedBits()
+	return nil
+}
+
+// AtLeastK sets the bitlist to have a bit set wherever at least k of the provided bitlists have
+// that bit set. The receiver is overwritten, and it is safe to pass it as one of the inputs.
+// Votes are tallied with bit-sliced counters, so the cost is linear in the number of inputs.
+// This method will return an error if the bitlists are not the same length.
+func (b *Bitlist64) AtLeastK(k uint64, srcs ...*Bitlist64) error {
+	if err := b.checkManyLen("Bitlist64.AtLeastK", srcs); err != nil {
+		return err
+	}
+
+	counter := newSlicedCounter[uint64](len(srcs))
+	for idx := range b.data {
+		counter.reset()
+		for _, src := range srcs {
+			counter.add(src.data[idx])
+		}
+		b.data[idx] = counter.atLeast(k)
+	}
+	b.clearUnusedBits()
+	return nil
+}
+
+// checkManyLen makes sure that all the bitlists have the same length as the receiver. The name of
+// the calling method is used for error reporting.
+func (b *Bitlist64) checkManyLen(name string, srcs []*Bitlist64) error {
+	for _, src := range srcs {
+		if src.Len() != b.Len() {
+			return lengthMismatch(name, ErrBitlistDifferentLength, b.Len(), src.Len())
+		}
+	}
+	return nil
+}
+
+// OrMany sets the bitlist to the union (OR) of all the provided bitlists. The receiver is
+// overwritten, so no allocation takes place, and it is safe to pass it as one of the inputs.
+// With no inputs, all bits are cleared.
+// This method will return an error if the bitlists are not the same length.
+func (b Bitlist) OrMany(srcs ...Bitlist) error {
+	size := b.Len()
+	if err := b.checkManyLen("Bitlist.OrMany", srcs); err != nil || len(b) == 0 {
+		return err
+	}
+
+	for idx := range b {
+		word := uint8(0)
+		for _, src := range srcs {
+			word |= src[idx]
+		}
+		b[idx] = word
+	}
+	b.setLengthBit(size)
+	return nil
+}
+
+// AndMany sets the bitlist to the intersection (AND) of all the provided bitlists. The receiver is
+// overwritten, so no allocation takes place, and it is safe to pass it as one of the inputs.
+// With no inputs, all bits are set.
+// This method will return an error if the bitlists are not the same length.
+func (b Bitlist) AndMany(srcs ...Bitlist) error {
+	size := b.Len()
+	if err := b.checkManyLen("Bitlist.AndMany", srcs); err != nil || len(b) == 0 {
+		return err
+	}
+
+	for idx := range b {
+		word := uint8(0xff)
+		for _, src := range srcs {
+			word &= src[idx]
+		}
+		b[idx] = word
+	}
+	b.setLengthBit(size)
+	return nil
+}
+
+// AtLeastK sets the bitlist to have a bit set wherever at least k of the provided bitlists have
+// that bit set. The receiver is overwritten, and it is safe to pass it as one of the inputs.
+// Votes are tallied with bit-sliced counters, so the cost is linear in the number of inputs.
+// This method will return an error if the bitlists are not the same length.
+func (b Bitlist) AtLeastK(k uint64, srcs ...Bitlist) error {
+	size := b.Len()
+	if err := b.checkManyLen("Bitlist.AtLeastK", srcs); err != nil || len(b) == 0 {
+		return err
+	}
+
+	counter := newSlicedCounter[uint8](len(srcs))
+	for idx := range b {
+		counter.reset()
+		for _, src := range srcs {
+			counter.add(src[idx])
+		}
+		b[idx] = counter.atLeast(k)
+	}
+	b.setLengthBit(size)
+	return nil
+}
+
+// checkManyLen makes sure that all the bitlists have the same length as the receiver. The name of
+// the calling method is used for error reporting.
+func (b Bitlist) checkManyLen(name string, srcs []Bitlist) error {
+	size := b.Len()
+	for _, src := range srcs {
+		if src.Len() != size || len(src) != len(b) {
+			return lengthMismatch(name, ErrBitlistDifferentLength, size, src.Len())
+		}
+	}
+	return nil
+}
+
+// slicedCounter keeps a vertical (bit-sliced) counter for every bit lane of a word: plane p holds
+// bit p of every lane's counter. Adding a word increments the counters of all lanes that have a bit
+// set, using a ripple carry across the planes.
+type slicedCounter[T word] struct {
+	planes []T
+}
+
+// newSlicedCounter creates a counter able to count up to n in every lane.
+func newSlicedCounter[T word](n int) *slicedCounter[T] {
+	return &slicedCounter[T]{
+		planes: make([]T, bits.Len64(uint64(n))),
+	}
+}
+
+// reset zeroes the counters of all lanes.
+func (c *slicedCounter[T]) reset() {
+	for p := range c.planes {
+		c.planes[p] = 0
+	}
+}
+
+// add increments the counters of the lanes which have a bit set in x.
+func (c *slicedCounter[T]) add(x T) {
+	for p := 0; x != 0 && p < len(c.planes); p++ {
+		carry := c.planes[p] & x
+		c.planes[p] ^= x
+		x = carry
+	}
+}
+
+// atLeast returns a word with bits set for the lanes whose counter is greater than or equal to k.
+func (c *slicedCounter[T]) atLeast(k uint64) T {
+	if bits.Len64(k) > len(c.planes) {
+		// The counters are not wide enough to ever reach k.
+		return 0
+	}
+
+	// Compare counters against k, going from the most significant plane down. The `eq` mask tracks
+	// lanes equal to k so far, and `gt` tracks lanes which are already known to be greater.
+	eq, gt := ^T(0), T(0)
+	for p := len(c.planes) - 1; p >= 0; p-- {
+		if k&(1<<uint(p)) != 0 {
+			eq &= c.planes[p]
+		} else {
+			gt |= eq & c.planes[p]
+			eq &^= c.planes[p]
+		}
+	}
+	return gt | eq
+}
@@ -0,0 +1,157 @@
+package bitfield
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// randomBitlists64 creates n bitlists of the given size, with bits set with probability 1/2.
+func randomBitlists64(r *rand.Rand, n int, size uint64) []*Bitlist64 {
+	ret := make([]*Bitlist64, n)
+	for i := range ret {
+		ret[i] = NewBitlist64(size)
+		for j := uint64(0); j < size; j++ {
+			ret[i].SetBitAt(j, r.Intn(2) == 1)
+		}
+	}
+	return ret
+}
+
+// votes returns how many of the bitfields have the bit at idx set.
+func votes(srcs []Bitfield, idx uint64) uint64 {
+	cnt := uint64(0)
+	for _, src := range srcs {
+		if src.BitAt(idx) {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func TestBitlist64_Many(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []uint64{0, 1, 63, 64, 65, 200} {
+		for _, n := range []int{0, 1, 2, 3, 7, 8, 33} {
+			srcs := randomBitlists64(r, n, size)
+			generic := make([]Bitfield, n)
+			for i, src := range srcs {
+				generic[i] = src
+			}
+			t.Run(fmt.Sprintf("size:%d,n:%d", size, n), func(t *testing.T) {
+				check := func(name string, apply func(dst *Bitlist64) error, want func(idx uint64) bool) {
+					dst := NewBitlist64(size)
+					// Make sure that no existing bits set interfere with operation.
+					for i := uint64(0); i < size; i += 5 {
+						dst.SetBitAt(i, true)
+					}
+					if err := apply(dst); err != nil {
+						t.Fatalf("%s: unexpected error: %v", name, err)
+					}
+					wanted := NewBitlist64(size)
+					for i := uint64(0); i < size; i++ {
+						wanted.SetBitAt(i, want(i))
+					}
+					if !reflect.DeepEqual(dst, wanted) {
+						t.Errorf("%s = %#x, wanted %#x", name, dst.data, wanted.data)
+					}
+				}
+
+				check("OrMany", func(dst *Bitlist64) error { return dst.OrMany(srcs...) },
+					func(idx uint64) bool { return votes(generic, idx) > 0 })
+				check("AndMany", func(dst *Bitlist64) error { return dst.AndMany(srcs...) },
+					func(idx uint64) bool { return votes(generic, idx) == uint64(n) })
+				for k := uint64(0); k <= uint64(n)+1; k++ {
+					check(fmt.Sprintf("AtLeastK(%d)", k), func(dst *Bitlist64) error { return dst.AtLeastK(k, srcs...) },
+						func(idx uint64) bool { return votes(generic, idx) >= k })
+				}
+			})
+		}
+	}
+
+	t.Run("destination is one of the inputs", func(t *testing.T) {
+		a := NewBitlist64From([]uint64{0b0011})
+		b := NewBitlist64From([]uint64{0b0101})
+		c := NewBitlist64From([]uint64{0b1001})
+		if err := a.AtLeastK(2, a, b, c); err != nil {
+			t.Fatal(err)
+		}
+		if a.data[0] != 0b0001 {
+			t.Errorf("AtLeastK(2) = %#b, wanted %#b", a.data[0], 0b0001)
+		}
+	})
+
+	t.Run("check errors", func(t *testing.T) {
+		dst := NewBitlist64(64)
+		a := NewBitlist64(64)
+		b := NewBitlist64(128)
+		if err := dst.OrMany(a, b); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+		if err := dst.AndMany(a, b); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+		if err := dst.AtLeastK(1, a, b); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+	})
+}
+
+func TestBitlist_Many(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []uint64{0, 1, 7, 8, 9, 100} {
+		for _, n := range []int{0, 1, 2, 5, 16} {
+			srcs := make([]Bitlist, n)
+			generic := make([]Bitfield, n)
+			for i, src := range randomBitlists64(r, n, size) {
+				srcs[i] = src.ToBitlist()
+				generic[i] = srcs[i]
+			}
+			t.Run(fmt.Sprintf("size:%d,n:%d", size, n), func(t *testing.T) {
+				check := func(name string, apply func(dst Bitlist) error, want func(idx uint64) bool) {
+					dst := NewBitlist(size)
+					for i := uint64(0); i < size; i += 5 {
+						dst.SetBitAt(i, true)
+					}
+					if err := apply(dst); err != nil {
+						t.Fatalf("%s: unexpected error: %v", name, err)
+					}
+					wanted := NewBitlist(size)
+					for i := uint64(0); i < size; i++ {
+						wanted.SetBitAt(i, want(i))
+					}
+					if !bytes.Equal(dst, wanted) {
+						t.Errorf("%s = %#x, wanted %#x", name, dst, wanted)
+					}
+				}
+
+				check("OrMany", func(dst Bitlist) error { return dst.OrMany(srcs...) },
+					func(idx uint64) bool { return votes(generic, idx) > 0 })
+				check("AndMany", func(dst Bitlist) error { return dst.AndMany(srcs...) },
+					func(idx uint64) bool { return votes(generic, idx) == uint64(n) })
+				for k := uint64(0); k <= uint64(n)+1; k++ {
+					check(fmt.Sprintf("AtLeastK(%d)", k), func(dst Bitlist) error { return dst.AtLeastK(k, srcs...) },
+						func(idx uint64) bool { return votes(generic, idx) >= k })
+				}
+			})
+		}
+	}
+
+	t.Run("check errors", func(t *testing.T) {
+		dst := NewBitlist(8)
+		a := NewBitlist(8)
+		b := NewBitlist(9)
+		if err := dst.OrMany(a, b); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+		if err := dst.AndMany(a, b); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+		if err := dst.AtLeastK(1, a, b); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+	})
+}
@@ -0,0 +1,278 @@
+//go:build linux
+
+package bitfield
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var _ = Bitfield(&MmapBitlist64{})
+
+// The file backing a MmapBitlist64 starts with a header of mmapHeaderSize bytes:
+//
+//	magic (4 bytes) | version (uint32) | bit length (uint64) | reserved (16 zero bytes)
+//
+// followed by the words of the bitlist. All integers are little-endian, and the file size must be
+// exactly the header size plus 8 bytes per word.
+const (
+	mmapHeaderSize = 32
+	mmapVersion    = 1
+	// mmapMaxBits bounds the bit length accepted from a header, so that the file size computed from
+	// it can't overflow.
+	mmapMaxBits = uint64(1) << 60
+)
+
+var mmapMagic = []byte("BL64")
+
+// MmapMode selects how a MmapBitlist64 file is opened.
+type MmapMode int
+
+const (
+	// MmapReadOnly maps the file read-only. Modifying the bits panics.
+	MmapReadOnly MmapMode = iota
+	// MmapReadWrite maps the file read-write. Modifications are written back to the file.
+	MmapReadWrite
+)
+
+// MmapBitlist64 is a bitlist stored in a memory-mapped file, for sets too large to comfortably keep
+// on the heap. Its bits are laid out like the ones of a Bitlist64, and can be read as one through
+// the View method, or modified as one through the Bitlist64 method if the file is mapped read-write.
+//
+// A MmapBitlist64 must be closed with Close, after which it must not be used anymore.
+type MmapBitlist64 struct {
+	bits    *Bitlist64
+	mapping []byte
+	file    *os.File
+	mode    MmapMode
+}
+
+// CreateMmapBitlist64 creates a new file at path, holding a bitlist of size `n` with all bits unset,
+// and maps it read-write.
+// This method will return an error if the file already exists.
+func CreateMmapBitlist64(path string, n uint64) (*MmapBitlist64, error) {
+	if err := checkNativeEndian(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, mmapHeaderSize)
+	copy(header, mmapMagic)
+	binary.LittleEndian.PutUint32(header[4:], mmapVersion)
+	binary.LittleEndian.PutUint64(header[8:], n)
+	size := int64(mmapHeaderSize + numWordsRequired(n)*bytesInWord)
+	if _, err = f.WriteAt(header, 0); err != nil {
+		return nil, removeOnError(f, err)
+	}
+	if err = f.Truncate(size); err != nil {
+		return nil, removeOnError(f, err)
+	}
+	b, err := mapBitlist64(f, n, size, MmapReadWrite)
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	return b, nil
+}
+
+// OpenMmapBitlist64 maps an existing file created by CreateMmapBitlist64, read-only or read-write.
+// This method will return an error if the header is invalid, or if the file size does not match the
+// bit length recorded in the header.
+func OpenMmapBitlist64(path string, mode MmapMode) (*MmapBitlist64, error) {
+	if err := checkNativeEndian(); err != nil {
+		return nil, err
+	}
+	flag := os.O_RDONLY
+	if mode == MmapReadWrite {
+		flag = os.O_RDWR
+	}
+	f, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, mmapHeaderSize)
+	if _, err = f.ReadAt(header, 0); err != nil {
+		return nil, closeOnError(f, fmt.Errorf("OpenMmapBitlist64: %w: reading header: %v", ErrInvalidEncoding, err))
+	}
+	if !bytes.Equal(header[:4], mmapMagic) {
+		return nil, closeOnError(f, fmt.Errorf("OpenMmapBitlist64: %w: bad magic %q", ErrInvalidEncoding, header[:4]))
+	}
+	if version := binary.LittleEndian.Uint32(header[4:]); version != mmapVersion {
+		return nil, closeOnError(f, fmt.Errorf("OpenMmapBitlist64: %w: unsupported version %d", ErrInvalidEncoding, version))
+	}
+	n := binary.LittleEndian.Uint64(header[8:])
+	if n > mmapMaxBits {
+		return nil, closeOnError(f, fmt.Errorf("OpenMmapBitlist64: %w: bad bit length %d", ErrInvalidEncoding, n))
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, closeOnError(f, err)
+	}
+	size := int64(mmapHeaderSize + numWordsRequired(n)*bytesInWord)
+	if info.Size() != size {
+		return nil, closeOnError(f, fmt.Errorf("OpenMmapBitlist64: %w: file is %d bytes, expected %d for %d bits",
+			ErrInvalidEncoding, info.Size(), size, n))
+	}
+	return mapBitlist64(f, n, size, mode)
+}
+
+// mapBitlist64 maps the first size bytes of f, which holds a bitlist of n bits. f is closed if an
+// error is returned.
+func mapBitlist64(f *os.File, n uint64, size int64, mode MmapMode) (*MmapBitlist64, error) {
+	prot := syscall.PROT_READ
+	if mode == MmapReadWrite {
+		prot |= syscall.PROT_WRITE
+	}
+	mapping, err := syscall.Mmap(int(f.Fd()), 0, int(size), prot, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, closeOnError(f, err)
+	}
+
+	// The mapping is page aligned, so the words after the header are aligned too.
+	data := []uint64{}
+	if numWords := numWordsRequired(n); numWords > 0 {
+		data = unsafe.Slice((*uint64)(unsafe.Pointer(&mapping[mmapHeaderSize])), numWords)
+	}
+	return &MmapBitlist64{
+		bits:    &Bitlist64{size: n, data: data},
+		mapping: mapping,
+		file:    f,
+		mode:    mode,
+	}, nil
+}
+
+// View returns a read-only view of the bits, backed by the mapping. It must not be used after Close.
+func (b *MmapBitlist64) View() *ReadOnlyBitlist64 {
+	return NewReadOnlyBitlist64(b.bits)
+}
+
+// Bitlist64 returns a Bitlist64 backed by the mapping, that can be passed to any function modifying
+// a Bitlist64 in place. It must not be resized, as growing it would move the bits off the mapping,
+// and must not be used after Close.
+// This method will return an error if the file is mapped read-only, as writing to the mapping would
+// crash the process.
+func (b *MmapBitlist64) Bitlist64() (*Bitlist64, error) {
+	if b.mode != MmapReadWrite {
+		return nil, fmt.Errorf("MmapBitlist64.Bitlist64: %w", ErrReadOnly)
+	}
+	return b.bits, nil
+}
+
+// BitAt returns the bit value at the given index. If the index requested
+// exceeds the number of bits in the bitlist, then this method returns false.
+func (b *MmapBitlist64) BitAt(idx uint64) bool {
+	return b.bits.BitAt(idx)
+}
+
+// SetBitAt sets the bit at the given index to val.
+//
+// If the index requested exceeds the number of bits in the bitlist, then this method does nothing.
+// It panics if the file is mapped read-only.
+func (b *MmapBitlist64) SetBitAt(idx uint64, val bool) {
+	b.mustBeWritable("MmapBitlist64.SetBitAt")
+	b.bits.SetBitAt(idx, val)
+}
+
+// Len returns the number of bits in the bitlist.
+func (b *MmapBitlist64) Len() uint64 {
+	return b.bits.Len()
+}
+
+// Count returns the number of 1s in the bitlist.
+func (b *MmapBitlist64) Count() uint64 {
+	return b.bits.Count()
+}
+
+// Bytes returns the bytes value of the bitlist, without the length bit. Leading zeros are trimmed
+// in the same way as for Bitlist64.
+func (b *MmapBitlist64) Bytes() []byte {
+	return b.bits.Bytes()
+}
+
+// BitIndices returns list of bit indexes of bitlist where value is set to true.
+func (b *MmapBitlist64) BitIndices() []int {
+	return b.bits.BitIndices()
+}
+
+// Or sets the bits of c in the bitlist, in place.
+// This method will return an error if the bitlists are of different lengths, or if the file is
+// mapped read-only.
+func (b *MmapBitlist64) Or(c *Bitlist64) error {
+	if b.mode != MmapReadWrite {
+		return fmt.Errorf("MmapBitlist64.Or: %w", ErrReadOnly)
+	}
+	if b.bits.Len() != c.Len() {
+		return lengthMismatch("MmapBitlist64.Or", ErrBitlistDifferentLength, b.bits.Len(), c.Len())
+	}
+	return b.bits.NoAllocOr(c, b.bits)
+}
+
+// Sync flushes the modifications to the file. It does nothing if the file is mapped read-only.
+func (b *MmapBitlist64) Sync() error {
+	if b.file == nil {
+		return os.ErrClosed
+	}
+	if b.mode != MmapReadWrite {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC,
+		uintptr(unsafe.Pointer(&b.mapping[0])), uintptr(len(b.mapping)), syscall.MS_SYNC)
+	if errno != 0 {
+		return fmt.Errorf("MmapBitlist64.Sync: %w", errno)
+	}
+	return nil
+}
+
+// Close flushes the modifications, unmaps the file and closes it.
+func (b *MmapBitlist64) Close() error {
+	if b.file == nil {
+		return os.ErrClosed
+	}
+	err := b.Sync()
+	if unmapErr := syscall.Munmap(b.mapping); err == nil {
+		err = unmapErr
+	}
+	if closeErr := b.file.Close(); err == nil {
+		err = closeErr
+	}
+	b.bits, b.mapping, b.file = nil, nil, nil
+	return err
+}
+
+// mustBeWritable panics if the file is mapped read-only.
+func (b *MmapBitlist64) mustBeWritable(name string) {
+	if b.mode != MmapReadWrite {
+		panic(fmt.Errorf("%s: %w", name, ErrReadOnly))
+	}
+}
+
+// closeOnError closes f, and returns err.
+func closeOnError(f *os.File, err error) error {
+	_ = f.Close()
+	return err
+}
+
+// removeOnError closes and removes f, and returns err.
+func removeOnError(f *os.File, err error) error {
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	return err
+}
+
+// checkNativeEndian returns an error on big-endian platforms, where the words of the mapping would
+// not match the little-endian file format.
+func checkNativeEndian() error {
+	if !nativeLittleEndian {
+		return fmt.Errorf("MmapBitlist64: %w", syscall.ENOTSUP)
+	}
+	return nil
+}
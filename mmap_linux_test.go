@@ -0,0 +1,181 @@
+//go:build linux
+
+package bitfield
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMmapBitlist64(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "participation")
+	size := uint64(1000)
+
+	b, err := CreateMmapBitlist64(path, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != size || b.Count() != 0 {
+		t.Errorf("new bitlist has length %d and count %d", b.Len(), b.Count())
+	}
+	b.SetBitAt(0, true)
+	b.SetBitAt(999, true)
+	b.SetBitAt(size, true)
+	other := NewBitlist64(size)
+	other.SetBitAt(500, true)
+	if err = b.Or(other); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Close(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("second Close() error = %v, wanted %v", err, os.ErrClosed)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := info.Size(), int64(mmapHeaderSize+16*8); got != want {
+		t.Errorf("file size = %d, wanted %d", got, want)
+	}
+
+	ro, err := OpenMmapBitlist64(path, MmapReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err = ro.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if got, want := ro.BitIndices(), []int{0, 500, 999}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BitIndices() = %v, wanted %v", got, want)
+	}
+	if !ro.View().Equal(*bitlist64Of(t, size, 0, 500, 999)) {
+		t.Errorf("View() = %v", ro.View().Clone())
+	}
+	if _, err = ro.Bitlist64(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Bitlist64() error = %v, wanted %v", err, ErrReadOnly)
+	}
+	if err = ro.Or(other); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Or() error = %v, wanted %v", err, ErrReadOnly)
+	}
+	func() {
+		defer func() {
+			if r, ok := recover().(error); !ok || !errors.Is(r, ErrReadOnly) {
+				t.Errorf("SetBitAt() on a read-only file panicked with %v, wanted %v", r, ErrReadOnly)
+			}
+		}()
+		ro.SetBitAt(1, true)
+	}()
+
+	rw, err := OpenMmapBitlist64(path, MmapReadWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw.SetBitAt(0, false)
+	bits, err := rw.Bitlist64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = bits.SetRange(1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if !rw.View().BitAt(2) {
+		t.Error("View() does not see the modifications made through Bitlist64()")
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Both mappings share the file.
+	if ro.BitAt(0) {
+		t.Error("read-only mapping does not see the modifications")
+	}
+}
+
+func bitlist64Of(t *testing.T, size uint64, indices ...uint64) *Bitlist64 {
+	t.Helper()
+	b := NewBitlist64(size)
+	for _, idx := range indices {
+		b.SetBitAt(idx, true)
+	}
+	return b
+}
+
+func TestMmapBitlist64_EmptyBitlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty")
+	b, err := CreateMmapBitlist64(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 0 || b.Count() != 0 || len(b.BitIndices()) != 0 {
+		t.Errorf("empty bitlist has length %d and count %d", b.Len(), b.Count())
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMmapBitlist64_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bits")
+	b, err := CreateMmapBitlist64(path, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Or(NewBitlist64(128)); !errors.Is(err, ErrBitlistDifferentLength) {
+		t.Errorf("Or() error = %v, wanted %v", err, ErrBitlistDifferentLength)
+	}
+	if err = b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = CreateMmapBitlist64(path, 64); !errors.Is(err, os.ErrExist) {
+		t.Errorf("CreateMmapBitlist64() error = %v, wanted %v", err, os.ErrExist)
+	}
+
+	valid, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modified := func(f func(data []byte) []byte) []byte {
+		return f(append([]byte{}, valid...))
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated header", data: valid[:10]},
+		{name: "bad magic", data: modified(func(d []byte) []byte { d[0] = 'X'; return d })},
+		{name: "bad version", data: modified(func(d []byte) []byte { d[4] = 2; return d })},
+		{name: "file too short", data: valid[:len(valid)-1]},
+		{name: "file too long", data: append(append([]byte{}, valid...), 0)},
+		{name: "length too large", data: modified(func(d []byte) []byte {
+			binary.LittleEndian.PutUint64(d[8:], 65)
+			return d
+		})},
+		{name: "huge length", data: modified(func(d []byte) []byte {
+			binary.LittleEndian.PutUint64(d[8:], 1<<63)
+			return d
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(dir, tt.name)
+			if err := os.WriteFile(p, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := OpenMmapBitlist64(p, MmapReadOnly); !errors.Is(err, ErrInvalidEncoding) {
+				t.Errorf("OpenMmapBitlist64() error = %v, wanted %v", err, ErrInvalidEncoding)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package bitfield
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+)
+
+// bitOrder describes how the bits of a bitfield are laid out in a byte stream. The package stores
+// bit i in byte i/8, at bit i%8 counting from the least significant bit, which is also the layout of
+// little-endian 64-bit words. Other orders only permute the bits within fixed size blocks.
+type bitOrder struct {
+	// blockBits is the size in bits of the blocks the bits are permuted within.
+	blockBits uint64
+	// mask is xor-ed with an index to find its position in the canonical order. As xor-ing twice
+	// cancels out, the same mask also maps a canonical position back to the index.
+	mask uint64
+	// trailing maps the offset of a bit within a trailing partial block of n bits to its offset in
+	// the canonical order, and trailingIndex maps it back. Views use them to keep the bits of a
+	// partial block in range.
+	trailing, trailingIndex func(offset, n uint64) uint64
+}
+
+var (
+	// msbFirst stores bit i in byte i/8, at bit i%8 counting from the most significant bit. The bits
+	// of a trailing partial byte are numbered from its most significant bit in range.
+	msbFirst = bitOrder{blockBits: 8, mask: 7, trailing: reverseBits, trailingIndex: reverseBits}
+	// bigEndianWords stores bit i in 64-bit word i/64, at bit i%64 counting from the least
+	// significant bit, with each word serialized in big-endian byte order. A trailing partial word
+	// is read as a big-endian number of as many bits, so that a 32-bit tail is a big-endian uint32.
+	bigEndianWords = bitOrder{
+		blockBits:     wordSize,
+		mask:          7 << 3,
+		trailing:      bigEndianTrailing,
+		trailingIndex: bigEndianTrailingIndex,
+	}
+)
+
+// position returns the position of the bit at the given index in the canonical order.
+func (o bitOrder) position(idx uint64) uint64 {
+	return idx ^ o.mask
+}
+
+// viewPosition returns the position in the canonical order of the bit at the given index of a view
+// of size bits. Unlike position, it maps the bits of a trailing partial block within the block.
+func (o bitOrder) viewPosition(idx, size uint64) uint64 {
+	start := size - size%o.blockBits
+	if idx < start {
+		return o.position(idx)
+	}
+	return start + o.trailing(idx-start, size-start)
+}
+
+// viewIndex is the inverse of viewPosition.
+func (o bitOrder) viewIndex(pos, size uint64) uint64 {
+	start := size - size%o.blockBits
+	if pos < start {
+		return o.position(pos)
+	}
+	return start + o.trailingIndex(pos-start, size-start)
+}
+
+// reverseBits maps the offset of a bit within a block of n bits to the offset counting from the
+// other end of the block.
+func reverseBits(offset, n uint64) uint64 {
+	return n - 1 - offset
+}
+
+// bigEndianTrailing maps the offset of a bit within a partial word of n bits, read as a big-endian
+// number, to its offset in the canonical order. The most significant byte comes first, and only
+// holds the bits of the number above the whole lower bytes.
+func bigEndianTrailing(offset, n uint64) uint64 {
+	numBytes := (n + 7) / 8
+	top := n - (numBytes-1)*8
+	if k := offset / 8; k < numBytes-1 {
+		return top + (numBytes-2-k)*8 + offset%8
+	}
+	return offset % 8
+}
+
+// bigEndianTrailingIndex is the inverse of bigEndianTrailing.
+func bigEndianTrailingIndex(pos, n uint64) uint64 {
+	numBytes := (n + 7) / 8
+	top := n - (numBytes-1)*8
+	if pos < top {
+		return (numBytes-1)*8 + pos
+	}
+	pos -= top
+	return (numBytes-2-pos/8)*8 + pos%8
+}
+
+// numBytes returns the number of bytes needed to hold n bits, padded to a whole block.
+func (o bitOrder) numBytes(n uint64) uint64 {
+	return (n + o.blockBits - 1) / o.blockBits * o.blockBits / 8
+}
+
+// ToMSBFirst returns the bits of b with bit i stored in byte i/8, at bit i%8 counting from the
+// most significant bit. The last byte is padded with zero bits. There is no length bit, so for
+// bitlists the length must be transmitted separately.
+func ToMSBFirst(b Bitfield) []byte {
+	return encodeBitOrder(b, msbFirst)
+}
+
+// FromMSBFirst sets the bits of dst from data, laid out as returned by ToMSBFirst. All the bits of
+// dst are overwritten, and dst is left unchanged if an error is returned.
+// This method will return an error if data is not of the size needed for the length of dst, or if
+// any padding bit is set.
+func FromMSBFirst(dst Bitfield, data []byte) error {
+	return decodeBitOrder("FromMSBFirst", dst, data, msbFirst)
+}
+
+// ToBigEndianWords returns the bits of b with bit i stored in 64-bit word i/64, at bit i%64 counting
+// from the least significant bit, and each word serialized in big-endian byte order. The last word
+// is padded with zero bits. There is no length bit, so for bitlists the length must be transmitted
+// separately.
+func ToBigEndianWords(b Bitfield) []byte {
+	return encodeBitOrder(b, bigEndianWords)
+}
+
+// FromBigEndianWords sets the bits of dst from data, laid out as returned by ToBigEndianWords. All
+// the bits of dst are overwritten, and dst is left unchanged if an error is returned.
+// This method will return an error if data is not of the size needed for the length of dst, or if
+// any padding bit is set.
+func FromBigEndianWords(dst Bitfield, data []byte) error {
+	return decodeBitOrder("FromBigEndianWords", dst, data, bigEndianWords)
+}
+
+// encodeBitOrder returns the bits of b laid out in the given order.
+func encodeBitOrder(b Bitfield, o bitOrder) []byte {
+	ret := make([]byte, o.numBytes(b.Len()))
+	for _, idx := range b.BitIndices() {
+		pos := o.position(uint64(idx))
+		ret[pos/8] |= 1 << (pos % 8)
+	}
+	return ret
+}
+
+// decodeBitOrder sets the bits of dst from data laid out in the given order. The name of the calling
+// function is used for error reporting.
+func decodeBitOrder(name string, dst Bitfield, data []byte, o bitOrder) error {
+	size := dst.Len()
+	if want := o.numBytes(size); uint64(len(data)) != want {
+		return lengthMismatch(name, ErrInvalidEncoding, uint64(len(data)), want)
+	}
+
+	indices := make([]uint64, 0)
+	for i, v := range data {
+		for v != 0 {
+			idx := o.position(uint64(i)*8 + uint64(bits.TrailingZeros8(v)))
+			if idx >= size {
+				return fmt.Errorf("%s: %w: padding bit %d is set", name, ErrInvalidEncoding, idx)
+			}
+			indices = append(indices, idx)
+			v &= v - 1
+		}
+	}
+
+	for _, idx := range dst.BitIndices() {
+		dst.SetBitAt(uint64(idx), false)
+	}
+	for _, idx := range indices {
+		dst.SetBitAt(idx, true)
+	}
+	return nil
+}
+
+// BitOrderView is a read-only view of a bitfield, that numbers its bits in a different order. It
+// lets bytes received in a foreign order be indexed as is, without converting them first: a view
+// of a bitfield holding the bytes returned by ToMSBFirst (or ToBigEndianWords) has the same bits
+// as the original bitfield.
+//
+// A view can be made of a bitfield of any length. If the length is not a multiple of the block the
+// bits are permuted within (a byte, or a 64-bit word), the bits of the trailing partial block are
+// numbered within that block, as described for each order. The bytes returned by ToMSBFirst or
+// ToBigEndianWords for such a length hold padding, and don't fit in a bitfield of that length.
+//
+// BitOrderView implements the read-only methods of Bitfield. Changes to the underlying bitfield are
+// visible through the view.
+type BitOrderView struct {
+	b     Bitfield
+	order bitOrder
+}
+
+// NewMSBFirstView returns a view of b that numbers the bits in each byte from the most significant
+// one, i.e. bit i of the view is bit 7-i%8 of byte i/8 of b. If the last byte of b only holds k < 8
+// bits, its bits are numbered from bit k-1.
+// This method will return an error if b is a bitvector too short to hold its bits.
+func NewMSBFirstView(b Bitfield) (*BitOrderView, error) {
+	return newBitOrderView("NewMSBFirstView", b, msbFirst)
+}
+
+// NewBigEndianWordsView returns a view of b that reads its bytes as big-endian 64-bit words, i.e.
+// bit i of the view is bit i%64 of the big-endian word i/64 of b. If the last word of b only holds
+// k < 64 bits, it is read as a big-endian number of k bits: a Bitvector32 is read as a big-endian
+// uint32, and a Bitvector4 as is.
+// This method will return an error if b is a bitvector too short to hold its bits.
+func NewBigEndianWordsView(b Bitfield) (*BitOrderView, error) {
+	return newBitOrderView("NewBigEndianWordsView", b, bigEndianWords)
+}
+
+// newBitOrderView returns a view of b in the given order. The name of the calling function is used
+// for error reporting.
+func newBitOrderView(name string, b Bitfield, o bitOrder) (*BitOrderView, error) {
+	if _, err := bitfieldBytes(name, b); err != nil {
+		return nil, err
+	}
+	return &BitOrderView{b: b, order: o}, nil
+}
+
+// BitAt returns the bit value at the given index of the view. If the index requested
+// exceeds the number of bits in the bitfield, then this method returns false.
+func (v *BitOrderView) BitAt(idx uint64) bool {
+	if idx >= v.b.Len() {
+		return false
+	}
+	return v.b.BitAt(v.order.viewPosition(idx, v.b.Len()))
+}
+
+// Len returns the number of bits in the bitfield.
+func (v *BitOrderView) Len() uint64 {
+	return v.b.Len()
+}
+
+// Count returns the number of 1s in the bitfield. Bits are only moved around, so this is the count
+// of the underlying bitfield.
+func (v *BitOrderView) Count() uint64 {
+	return v.b.Count()
+}
+
+// Bytes returns the bits of the view in the canonical order of the package. Leading zeros are
+// trimmed in the same way as for Bitlist64.
+func (v *BitOrderView) Bytes() []byte {
+	return v.ToBitlist64().Bytes()
+}
+
+// BitIndices returns list of bit indexes of the view where value is set to true.
+func (v *BitOrderView) BitIndices() []int {
+	size := v.b.Len()
+	indices := v.b.BitIndices()
+	for i, idx := range indices {
+		indices[i] = int(v.order.viewIndex(uint64(idx), size))
+	}
+	slices.Sort(indices)
+	return indices
+}
+
+// ToBitlist64 returns a copy of the bits of the view, in the canonical order of the package.
+func (v *BitOrderView) ToBitlist64() *Bitlist64 {
+	ret := NewBitlist64(v.b.Len())
+	for _, idx := range v.BitIndices() {
+		ret.SetBitAt(uint64(idx), true)
+	}
+	return ret
+}
@@ -0,0 +1,248 @@
+package bitfield
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestToMSBFirst(t *testing.T) {
+	tests := []struct {
+		b    Bitfield
+		want []byte
+	}{
+		{b: Bitvector8{0x01}, want: []byte{0x80}},
+		{b: Bitvector8{0x80}, want: []byte{0x01}},
+		{b: Bitvector8{0x0F}, want: []byte{0xF0}},
+		{b: Bitvector4{0x03}, want: []byte{0xC0}},
+		{b: Bitlist{0x01, 0x06}, want: []byte{0x80, 0x40}},
+		{b: NewBitlist64(0), want: []byte{}},
+	}
+	for _, tt := range tests {
+		if got := ToMSBFirst(tt.b); !bytes.Equal(got, tt.want) {
+			t.Errorf("ToMSBFirst(%#x) = %#x, wanted %#x", tt.b.Bytes(), got, tt.want)
+		}
+	}
+}
+
+func TestToBigEndianWords(t *testing.T) {
+	tests := []struct {
+		b    Bitfield
+		want []byte
+	}{
+		{b: NewBitlist64From([]uint64{0x01}), want: []byte{0, 0, 0, 0, 0, 0, 0, 0x01}},
+		{b: NewBitlist64From([]uint64{1 << 63}), want: []byte{0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{b: NewBitlist64From([]uint64{0x0102030405060708}), want: []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{b: Bitvector8{0x01}, want: []byte{0, 0, 0, 0, 0, 0, 0, 0x01}},
+		{
+			b:    Bitvector128{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			want: []byte{8, 7, 6, 5, 4, 3, 2, 1, 16, 15, 14, 13, 12, 11, 10, 9},
+		},
+	}
+	for _, tt := range tests {
+		if got := ToBigEndianWords(tt.b); !bytes.Equal(got, tt.want) {
+			t.Errorf("ToBigEndianWords(%#x) = %#x, wanted %#x", tt.b.Bytes(), got, tt.want)
+		}
+	}
+}
+
+func TestBitOrder_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	fill := func(b Bitfield) Bitfield {
+		for i := uint64(0); i < b.Len(); i++ {
+			b.SetBitAt(i, r.Intn(2) == 0)
+		}
+		return b
+	}
+	fields := []func() Bitfield{
+		func() Bitfield { return NewBitlist(13) },
+		func() Bitfield { return NewBitlist(64) },
+		func() Bitfield { return NewBitlist64(0) },
+		func() Bitfield { return NewBitlist64(100) },
+		func() Bitfield { return NewBitvector4() },
+		func() Bitfield { return NewBitvector8() },
+		func() Bitfield { return NewBitvector32() },
+		func() Bitfield { return NewBitvector64() },
+		func() Bitfield { return NewBitvector128() },
+		func() Bitfield { return NewBitvector256() },
+		func() Bitfield { return NewBitvector512() },
+		func() Bitfield { return NewSparseBitlist(300) },
+	}
+	orders := []struct {
+		name   string
+		encode func(Bitfield) []byte
+		decode func(Bitfield, []byte) error
+	}{
+		{name: "msb first", encode: ToMSBFirst, decode: FromMSBFirst},
+		{name: "big-endian words", encode: ToBigEndianWords, decode: FromBigEndianWords},
+	}
+	for _, o := range orders {
+		for _, newField := range fields {
+			b := fill(newField())
+			t.Run(fmt.Sprintf("%s/%T/%d", o.name, b, b.Len()), func(t *testing.T) {
+				canonical := b.Bytes()
+				encoded := o.encode(b)
+				if !bytes.Equal(b.Bytes(), canonical) {
+					t.Fatalf("encoding changed the bitfield to %#x, wanted %#x", b.Bytes(), canonical)
+				}
+
+				// Decode into a bitfield with every bit set, to make sure that all bits are overwritten.
+				got := newField()
+				for i := uint64(0); i < got.Len(); i++ {
+					got.SetBitAt(i, true)
+				}
+				if err := o.decode(got, encoded); err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got.Bytes(), canonical) {
+					t.Errorf("round trip = %#x, wanted %#x", got.Bytes(), canonical)
+				}
+			})
+		}
+	}
+}
+
+func TestBitOrder_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func(Bitfield, []byte) error
+		dst    Bitfield
+		data   []byte
+		want   error
+	}{
+		{name: "msb first too short", decode: FromMSBFirst, dst: NewBitlist(9), data: []byte{0x00}, want: ErrInvalidEncoding},
+		{name: "msb first too long", decode: FromMSBFirst, dst: NewBitvector8(), data: []byte{0, 0}, want: ErrInvalidEncoding},
+		{name: "msb first padding", decode: FromMSBFirst, dst: NewBitvector4(), data: []byte{0x08}, want: ErrInvalidEncoding},
+		{name: "words not whole", decode: FromBigEndianWords, dst: NewBitvector8(), data: []byte{0}, want: ErrInvalidEncoding},
+		{name: "words padding", decode: FromBigEndianWords, dst: NewBitlist64(8), data: []byte{0, 0, 0, 0, 0, 0, 0x01, 0}, want: ErrInvalidEncoding},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.dst.SetBitAt(0, true)
+			if err := tt.decode(tt.dst, tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("decode error = %v, wanted %v", err, tt.want)
+			}
+			if !tt.dst.BitAt(0) || tt.dst.Count() != 1 {
+				t.Error("decode modified the bitfield on error")
+			}
+		})
+	}
+}
+
+func TestBitOrderView(t *testing.T) {
+	original := NewBitlist64(128)
+	for _, idx := range []uint64{0, 3, 9, 63, 64, 100, 127} {
+		original.SetBitAt(idx, true)
+	}
+
+	views := []struct {
+		name    string
+		encode  func(Bitfield) []byte
+		newView func(Bitfield) (*BitOrderView, error)
+	}{
+		{name: "msb first", encode: ToMSBFirst, newView: NewMSBFirstView},
+		{name: "big-endian words", encode: ToBigEndianWords, newView: NewBigEndianWordsView},
+	}
+	for _, tt := range views {
+		t.Run(tt.name, func(t *testing.T) {
+			// Bytes received in the foreign order are loaded as is, and read through the view.
+			raw := Bitvector128(tt.encode(original))
+			view, err := tt.newView(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if view.Len() != original.Len() || view.Count() != original.Count() {
+				t.Errorf("view has %d bits with %d set, wanted %d with %d", view.Len(), view.Count(), original.Len(), original.Count())
+			}
+			for i := uint64(0); i <= original.Len(); i++ {
+				if view.BitAt(i) != original.BitAt(i) {
+					t.Errorf("BitAt(%d) = %v, wanted %v", i, view.BitAt(i), original.BitAt(i))
+				}
+			}
+			if got, want := fmt.Sprint(view.BitIndices()), fmt.Sprint(original.BitIndices()); got != want {
+				t.Errorf("BitIndices() = %s, wanted %s", got, want)
+			}
+			if !view.ToBitlist64().Equal(*original) || !bytes.Equal(view.Bytes(), original.Bytes()) {
+				t.Errorf("ToBitlist64() = %+v, wanted %+v", view.ToBitlist64(), original)
+			}
+
+			// Changes to the underlying bitfield show through the view.
+			raw.SetBitAt(view.order.viewPosition(1, raw.Len()), true)
+			if !view.BitAt(1) {
+				t.Error("view does not share memory with the bitfield")
+			}
+		})
+	}
+}
+
+func TestBitOrderView_PartialBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		newView func(Bitfield) (*BitOrderView, error)
+		b       Bitfield
+		set     []uint64
+	}{
+		// The 4 bits of a Bitvector4 are numbered from bit 3.
+		{name: "msb first nibble", newView: NewMSBFirstView, b: Bitvector4{0x01}, set: []uint64{3}},
+		// The trailing 4 bits of a 12-bit bitlist are numbered from bit 11.
+		{name: "msb first bitlist", newView: NewMSBFirstView, b: Bitlist{0x01, 0x12}, set: []uint64{7, 10}},
+		// A Bitvector4 and a Bitvector8 are read as is.
+		{name: "words nibble", newView: NewBigEndianWordsView, b: Bitvector4{0x05}, set: []uint64{0, 2}},
+		{name: "words byte", newView: NewBigEndianWordsView, b: Bitvector8{0x81}, set: []uint64{0, 7}},
+		// A Bitvector32 is read as a big-endian uint32.
+		{name: "words uint32", newView: NewBigEndianWordsView, b: Bitvector32{0x01, 0, 0, 0x80}, set: []uint64{7, 24}},
+		// The last byte of the first word is its least significant one. The trailing 12 bits of the
+		// 76-bit bitlist are read as a big-endian number, of which the top 4 bits come first.
+		{
+			name:    "words bitlist",
+			newView: NewBigEndianWordsView,
+			b:       Bitlist{0, 0, 0, 0, 0, 0, 0, 0x01, 0x13, 0x10},
+			set:     []uint64{0, 64 + 8, 64 + 9, 64},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view, err := tt.newView(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := NewBitlist64(tt.b.Len())
+			for _, idx := range tt.set {
+				want.SetBitAt(idx, true)
+			}
+			for i := uint64(0); i < view.Len(); i++ {
+				if view.BitAt(i) != want.BitAt(i) {
+					t.Errorf("BitAt(%d) = %v, wanted %v", i, view.BitAt(i), want.BitAt(i))
+				}
+			}
+			if !view.ToBitlist64().Equal(*want) {
+				t.Errorf("ToBitlist64() = %v, wanted %v", view.BitIndices(), want.BitIndices())
+			}
+		})
+	}
+
+	// Every bit of any length is mapped to exactly one bit of the view.
+	for _, o := range []bitOrder{msbFirst, bigEndianWords} {
+		for size := uint64(0); size <= 200; size++ {
+			seen := make(map[uint64]bool)
+			for i := uint64(0); i < size; i++ {
+				pos := o.viewPosition(i, size)
+				if pos >= size || seen[pos] {
+					t.Fatalf("block of %d bits: bit %d of a %d-bit view maps to %d", o.blockBits, i, size, pos)
+				}
+				seen[pos] = true
+				if got := o.viewIndex(pos, size); got != i {
+					t.Fatalf("block of %d bits: viewIndex(%d, %d) = %d, wanted %d", o.blockBits, pos, size, got, i)
+				}
+			}
+		}
+	}
+}
+
+func TestBitOrderView_MalformedBitvector(t *testing.T) {
+	if _, err := NewBigEndianWordsView(Bitvector128{0x01, 0x02}); !errors.Is(err, ErrWrongLen) {
+		t.Errorf("NewBigEndianWordsView() error = %v, wanted %v", err, ErrWrongLen)
+	}
+}
@@ -0,0 +1,213 @@
+package bitfield
+
+import (
+	"math/bits"
+)
+
+const (
+	// persistentLeafWords is the number of words held by each leaf of a persistent bitlist.
+	persistentLeafWords = 8
+	// persistentLeafBitsLog2 = log_2(persistentLeafWords * wordSize)
+	persistentLeafBitsLog2 = 9
+	// persistentBranching is the number of children of each inner node of a persistent bitlist.
+	persistentBranching = 32
+	// persistentBranchingLog2 = log_2(persistentBranching)
+	persistentBranchingLog2 = 5
+)
+
+// PersistentBitlist is an immutable bitlist, stored as a tree of fixed size chunks of words.
+// Modifying a bit creates a new version of the bitlist, which shares all unchanged chunks with the
+// previous one. This makes it cheap to keep many versions of a large bitlist, that only differ by a
+// few bits.
+//
+// PersistentBitlist implements the read-only methods of Bitfield. It is safe for concurrent use.
+type PersistentBitlist struct {
+	size  uint64
+	depth uint64
+	root  *persistentNode
+}
+
+// persistentNode is either a leaf holding words, or an inner node holding children.
+// Nodes are never modified once they are part of a tree.
+type persistentNode struct {
+	count    uint64
+	words    []uint64
+	children []*persistentNode
+}
+
+// NewPersistentBitlist creates a new persistent bitlist of size `n`, with all bits unset.
+func NewPersistentBitlist(n uint64) *PersistentBitlist {
+	return NewPersistentBitlistFrom(NewBitlist64(n))
+}
+
+// NewPersistentBitlistFrom creates a new persistent bitlist holding the same bits as b.
+// The bits are copied, so b can be modified afterwards.
+func NewPersistentBitlistFrom(b *Bitlist64) *PersistentBitlist {
+	numLeaves := (numWordsRequired(b.size) + persistentLeafWords - 1) / persistentLeafWords
+	nodes := make([]*persistentNode, max(numLeaves, 1))
+	for i := range nodes {
+		words := make([]uint64, persistentLeafWords)
+		if start := i * persistentLeafWords; start < len(b.data) {
+			copy(words, b.data[start:min(start+persistentLeafWords, len(b.data))])
+		}
+		nodes[i] = newPersistentLeaf(words)
+	}
+
+	depth := uint64(0)
+	for len(nodes) > 1 {
+		parents := make([]*persistentNode, (len(nodes)+persistentBranching-1)/persistentBranching)
+		for i := range parents {
+			parents[i] = newPersistentInner(nodes[i*persistentBranching : min((i+1)*persistentBranching, len(nodes))])
+		}
+		nodes = parents
+		depth++
+	}
+
+	return &PersistentBitlist{
+		size:  b.size,
+		depth: depth,
+		root:  nodes[0],
+	}
+}
+
+// BitAt returns the bit value at the given index. If the index requested
+// exceeds the number of bits in the bitlist, then this method returns false.
+func (b *PersistentBitlist) BitAt(idx uint64) bool {
+	if idx >= b.size {
+		return false
+	}
+
+	node := b.root
+	for level := b.depth; level > 0; level-- {
+		node = node.children[persistentChildIndex(idx, level)]
+	}
+	return node.words[persistentWordIndex(idx)]&(1<<(idx%wordSize)) != 0
+}
+
+// With returns a version of the bitlist with the bit at the given index set to val. The receiver is
+// left unchanged. Only the chunks on the path to the modified bit are copied, all other chunks are
+// shared between the two versions. If the index exceeds the number of bits in the bitlist, or the
+// bit already has the requested value, the receiver is returned as is.
+func (b *PersistentBitlist) With(idx uint64, val bool) *PersistentBitlist {
+	if idx >= b.size || b.BitAt(idx) == val {
+		return b
+	}
+
+	return &PersistentBitlist{
+		size:  b.size,
+		depth: b.depth,
+		root:  b.root.with(idx, b.depth, val),
+	}
+}
+
+// Len returns the number of bits in a bitlist.
+func (b *PersistentBitlist) Len() uint64 {
+	return b.size
+}
+
+// Count returns the number of 1s in the bitlist. The count is maintained per chunk, so this runs in
+// constant time.
+func (b *PersistentBitlist) Count() uint64 {
+	return b.root.count
+}
+
+// Bytes returns the bytes value of the bitlist, without the length bit. Leading zeros are trimmed
+// in the same way as for Bitlist64.
+func (b *PersistentBitlist) Bytes() []byte {
+	return b.ToBitlist64().Bytes()
+}
+
+// BitIndices returns list of bit indexes of bitlist where value is set to true. Chunks without any
+// set bits are skipped.
+func (b *PersistentBitlist) BitIndices() []int {
+	indices := make([]int, 0, b.Count())
+	b.root.walk(0, b.depth, func(offset uint64, words []uint64) {
+		for i, word := range words {
+			for word != 0 {
+				indices = append(indices, int(offset)+i<<wordSizeLog2+bits.TrailingZeros64(word))
+				word &= word - 1
+			}
+		}
+	})
+	return indices
+}
+
+// ToBitlist64 returns a mutable copy of the bitlist.
+func (b *PersistentBitlist) ToBitlist64() *Bitlist64 {
+	ret := NewBitlist64(b.size)
+	b.root.walk(0, b.depth, func(offset uint64, words []uint64) {
+		start := offset >> wordSizeLog2
+		copy(ret.data[start:], words)
+	})
+	return ret
+}
+
+// newPersistentLeaf creates a leaf node, taking ownership of the words.
+func newPersistentLeaf(words []uint64) *persistentNode {
+	n := &persistentNode{words: words}
+	for _, word := range words {
+		n.count += uint64(bits.OnesCount64(word))
+	}
+	return n
+}
+
+// newPersistentInner creates an inner node, copying the list of children.
+func newPersistentInner(children []*persistentNode) *persistentNode {
+	n := &persistentNode{children: make([]*persistentNode, len(children))}
+	copy(n.children, children)
+	for _, child := range children {
+		n.count += child.count
+	}
+	return n
+}
+
+// with returns a copy of the node, with the bit at the given index flipped to val. The bit is
+// expected to currently hold the opposite value.
+func (n *persistentNode) with(idx, level uint64, val bool) *persistentNode {
+	var ret *persistentNode
+	if level == 0 {
+		ret = &persistentNode{count: n.count, words: make([]uint64, len(n.words))}
+		copy(ret.words, n.words)
+		ret.words[persistentWordIndex(idx)] ^= 1 << (idx % wordSize)
+	} else {
+		ret = &persistentNode{count: n.count, children: make([]*persistentNode, len(n.children))}
+		copy(ret.children, n.children)
+		child := persistentChildIndex(idx, level)
+		ret.children[child] = n.children[child].with(idx, level-1, val)
+	}
+
+	if val {
+		ret.count++
+	} else {
+		ret.count--
+	}
+	return ret
+}
+
+// walk calls fn for every leaf with at least one set bit, in order, along with the index of the
+// first bit of the leaf.
+func (n *persistentNode) walk(offset, level uint64, fn func(offset uint64, words []uint64)) {
+	if n.count == 0 {
+		return
+	}
+	if level == 0 {
+		fn(offset, n.words)
+		return
+	}
+	childBits := uint64(1) << (persistentLeafBitsLog2 + (level-1)*persistentBranchingLog2)
+	for i, child := range n.children {
+		child.walk(offset+uint64(i)*childBits, level-1, fn)
+	}
+}
+
+// persistentChildIndex returns the index of the child holding the given bit, in an inner node at
+// the given level above the leaves.
+func persistentChildIndex(idx, level uint64) uint64 {
+	shift := persistentLeafBitsLog2 + (level-1)*persistentBranchingLog2
+	return (idx >> shift) & (persistentBranching - 1)
+}
+
+// persistentWordIndex returns the index of the word holding the given bit, within its leaf.
+func persistentWordIndex(idx uint64) uint64 {
+	return (idx >> wordSizeLog2) & (persistentLeafWords - 1)
+}
@@ -0,0 +1,87 @@
+package bitfield
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPersistentBitlist_RoundTrip(t *testing.T) {
+	for _, size := range []uint64{0, 1, 63, 64, 511, 512, 513, 20000, 300000} {
+		b := NewBitlist64(size)
+		for i := uint64(0); i < size; i += 7 {
+			b.SetBitAt(i, true)
+		}
+
+		p := NewPersistentBitlistFrom(b)
+		if p.Len() != size {
+			t.Errorf("Len() = %d, wanted %d", p.Len(), size)
+		}
+		if p.Count() != b.Count() {
+			t.Errorf("size %d: Count() = %d, wanted %d", size, p.Count(), b.Count())
+		}
+		if !reflect.DeepEqual(p.BitIndices(), b.BitIndices()) {
+			t.Errorf("size %d: BitIndices() differ", size)
+		}
+		if !reflect.DeepEqual(p.Bytes(), b.Bytes()) {
+			t.Errorf("size %d: Bytes() differ", size)
+		}
+		if got := p.ToBitlist64(); !got.Equal(*b) {
+			t.Errorf("size %d: ToBitlist64() = %v, wanted %v", size, got, b)
+		}
+		for _, idx := range []uint64{0, 6, 7, size - 1, size, size + 100} {
+			if p.BitAt(idx) != b.BitAt(idx) {
+				t.Errorf("size %d: BitAt(%d) = %t, wanted %t", size, idx, p.BitAt(idx), b.BitAt(idx))
+			}
+		}
+	}
+}
+
+func TestPersistentBitlist_With(t *testing.T) {
+	size := uint64(100000)
+	v0 := NewPersistentBitlist(size)
+	v1 := v0.With(70000, true)
+	v2 := v1.With(3, true).With(99999, true)
+	v3 := v2.With(70000, false)
+
+	tests := []struct {
+		version *PersistentBitlist
+		want    []int
+	}{
+		{version: v0, want: []int{}},
+		{version: v1, want: []int{70000}},
+		{version: v2, want: []int{3, 70000, 99999}},
+		{version: v3, want: []int{3, 99999}},
+	}
+	for i, tt := range tests {
+		if got := tt.version.BitIndices(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("v%d.BitIndices() = %v, wanted %v", i, got, tt.want)
+		}
+		if got := tt.version.Count(); got != uint64(len(tt.want)) {
+			t.Errorf("v%d.Count() = %d, wanted %d", i, got, len(tt.want))
+		}
+	}
+
+	if v1.With(70000, true) != v1 {
+		t.Error("With() of an unchanged bit should return the receiver")
+	}
+	if v1.With(size, true) != v1 {
+		t.Error("With() out of range should return the receiver")
+	}
+
+	// Only the path to the modified bit is copied.
+	if v0.root.children[0] != v1.root.children[0] {
+		t.Error("unchanged chunks should be shared between versions")
+	}
+	if v0.root.children[persistentChildIndex(70000, v0.depth)] == v1.root.children[persistentChildIndex(70000, v1.depth)] {
+		t.Error("modified chunks should not be shared between versions")
+	}
+}
+
+func TestPersistentBitlist_ToBitlist64IsCopy(t *testing.T) {
+	p := NewPersistentBitlist(10).With(2, true)
+	b := p.ToBitlist64()
+	b.SetBitAt(5, true)
+	if p.BitAt(5) {
+		t.Error("modifying the result of ToBitlist64() should not modify the persistent bitlist")
+	}
+}
@@ -0,0 +1,65 @@
+package bitfield
+
+import (
+	"math/rand"
+	"reflect"
+)
+
+// The Generate methods in this file implement testing/quick.Generator, so that property-based tests
+// can take bitfields as arguments. Bitlists are generated with a random length of up to size bits.
+// Every bit is set with a probability of one half.
+
+// Generate returns a random Bitlist of up to size bits.
+func (Bitlist) Generate(r *rand.Rand, size int) reflect.Value {
+	n := uint64(r.Intn(size + 1))
+	b := NewBitlist(n)
+	for i := uint64(0); i < n; i++ {
+		b.SetBitAt(i, r.Intn(2) == 1)
+	}
+	return reflect.ValueOf(b)
+}
+
+// Generate returns a random Bitlist64 of up to size bits.
+func (*Bitlist64) Generate(r *rand.Rand, size int) reflect.Value {
+	n := uint64(r.Intn(size + 1))
+	b := NewBitlist64(n)
+	for i := uint64(0); i < n; i++ {
+		b.SetBitAt(i, r.Intn(2) == 1)
+	}
+	return reflect.ValueOf(b)
+}
+
+// Generate returns a random Bitvector4. The size hint is ignored.
+func (Bitvector4) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Bitvector4(populateVector(r, bitvector4ByteSize, bitvector4BitSize)))
+}
+
+// Generate returns a random Bitvector8. The size hint is ignored.
+func (Bitvector8) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Bitvector8(populateVector(r, bitvector8ByteSize, bitvector8BitSize)))
+}
+
+// Generate returns a random Bitvector32. The size hint is ignored.
+func (Bitvector32) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Bitvector32(populateVector(r, bitvector32ByteSize, bitvector32BitSize)))
+}
+
+// Generate returns a random Bitvector64. The size hint is ignored.
+func (Bitvector64) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Bitvector64(populateVector(r, bitvector64ByteSize, bitvector64BitSize)))
+}
+
+// Generate returns a random Bitvector128. The size hint is ignored.
+func (Bitvector128) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Bitvector128(populateVector(r, bitvector128ByteSize, bitvector128BitSize)))
+}
+
+// Generate returns a random Bitvector256. The size hint is ignored.
+func (Bitvector256) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Bitvector256(populateVector(r, bitvector256ByteSize, bitvector256BitSize)))
+}
+
+// Generate returns a random Bitvector512. The size hint is ignored.
+func (Bitvector512) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Bitvector512(populateVector(r, bitvector512ByteSize, bitvector512BitSize)))
+}
@@ -0,0 +1,54 @@
+package bitfield
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+var (
+	_ quick.Generator = Bitlist{}
+	_ quick.Generator = &Bitlist64{}
+	_ quick.Generator = Bitvector4{}
+	_ quick.Generator = Bitvector8{}
+	_ quick.Generator = Bitvector32{}
+	_ quick.Generator = Bitvector64{}
+	_ quick.Generator = Bitvector128{}
+	_ quick.Generator = Bitvector256{}
+	_ quick.Generator = Bitvector512{}
+)
+
+func TestGenerate_Bitlist(t *testing.T) {
+	// Not() twice is the identity, and preserves the length.
+	f := func(b Bitlist) bool {
+		return b.Not().Not().Equal(b) && b.Not().Count() == b.Len()-b.Count()
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenerate_Bitlist64(t *testing.T) {
+	// The Bitlist64 and Bitlist representations of the same bits agree.
+	f := func(b *Bitlist64) bool {
+		return EqualBitlists(b.ToBitlist(), b) && b.ToBitlist().Hash() == b.Hash()
+	}
+	if err := quick.Check(f, &quick.Config{MaxCountScale: 2}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenerate_Bitvectors(t *testing.T) {
+	f := func(b4 Bitvector4, b8 Bitvector8, b32 Bitvector32, b64 Bitvector64, b128 Bitvector128,
+		b256 Bitvector256, b512 Bitvector512) bool {
+		for _, b := range []Bitfield{b4, b8, b32, b64, b128, b256, b512} {
+			// Generated bitvectors are well formed, so their indices account for all set bits.
+			if uint64(len(b.BitIndices())) != b.Count() {
+				return false
+			}
+		}
+		return len(b4) == 1 && b4[0]&0xF0 == 0 && len(b512) == 64
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,217 @@
+// Package randbits generates random bitfields for simulations and property-based tests.
+// Bitfields are drawn from a math/rand/v2 source, so that a seeded source produces the same
+// bitfields on every run.
+package randbits
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// Generator draws random bitfields from a source. It is not safe for concurrent use.
+type Generator struct {
+	r *rand.Rand
+}
+
+// New creates a new generator drawing from the given source.
+func New(src rand.Source) *Generator {
+	return &Generator{r: rand.New(src)}
+}
+
+// NewSeeded creates a new generator drawing from a PCG source with the given seed.
+func NewSeeded(seed uint64) *Generator {
+	return New(rand.NewPCG(seed, seed))
+}
+
+// Distribution decides which bits of a generated bitfield are set.
+type Distribution interface {
+	// fill sets bits of b, which has all its bits unset.
+	fill(r *rand.Rand, b *bitfield.Bitlist64)
+}
+
+type density struct {
+	p float64
+}
+
+// Density sets every bit independently, with a probability of p.
+// It panics if p is not within [0, 1].
+func Density(p float64) Distribution {
+	checkProbability(p)
+	return density{p: p}
+}
+
+func (d density) fill(r *rand.Rand, b *bitfield.Bitlist64) {
+	for i := uint64(0); i < b.Len(); i++ {
+		if r.Float64() < d.p {
+			b.SetBitAt(i, true)
+		}
+	}
+}
+
+type clustered struct {
+	p       float64
+	meanRun float64
+}
+
+// Clustered sets bits in runs, such as validators of the same operator going offline together.
+// Runs of set bits have a geometrically distributed length with the given mean, and runs of unset
+// bits are sized so that a fraction p of all bits is set on average.
+// It panics if p is not within [0, 1], or if meanRun is less than 1.
+func Clustered(p, meanRun float64) Distribution {
+	checkProbability(p)
+	if meanRun < 1 {
+		panic(fmt.Sprintf("randbits: mean run length %v is less than 1", meanRun))
+	}
+	return clustered{p: p, meanRun: meanRun}
+}
+
+func (c clustered) fill(r *rand.Rand, b *bitfield.Bitlist64) {
+	switch c.p {
+	case 0:
+		return
+	case 1:
+		_ = b.SetRange(0, b.Len())
+		return
+	}
+
+	meanUnset := c.meanRun * (1 - c.p) / c.p
+	set := r.Float64() < c.p
+	for i := uint64(0); i < b.Len(); {
+		var run uint64
+		if set {
+			run = geometric(r, c.meanRun)
+		} else {
+			run = geometric(r, meanUnset)
+		}
+		end := min(i+run, b.Len())
+		if set {
+			_ = b.SetRange(i, end)
+		}
+		i, set = end, !set
+	}
+}
+
+// geometric returns a geometrically distributed run length of at least 1, with the given mean.
+func geometric(r *rand.Rand, mean float64) uint64 {
+	if mean <= 1 {
+		return 1
+	}
+	// Inverse transform sampling, 1-Float64() is in (0, 1] so the logarithm is finite.
+	n := math.Log(1-r.Float64()) / math.Log(1-1/mean)
+	if n >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return 1 + uint64(n)
+}
+
+type exactCount struct {
+	k uint64
+}
+
+// ExactCount sets exactly k bits, chosen uniformly at random. Generating a bitfield of fewer than k
+// bits panics.
+func ExactCount(k uint64) Distribution {
+	return exactCount{k: k}
+}
+
+func (e exactCount) fill(r *rand.Rand, b *bitfield.Bitlist64) {
+	n := b.Len()
+	if e.k > n {
+		panic(fmt.Sprintf("randbits: cannot set %d bits of a %d bit bitfield", e.k, n))
+	}
+
+	// Choose the smaller of the set and unset bits, and flip everything afterwards if needed.
+	k, flip := e.k, false
+	if k > n/2 {
+		k, flip = n-k, true
+	}
+	// Floyd's algorithm picks k distinct indices in O(k) steps.
+	for j := n - k; j < n; j++ {
+		if t := r.Uint64N(j + 1); !b.BitAt(t) {
+			b.SetBitAt(t, true)
+		} else {
+			b.SetBitAt(j, true)
+		}
+	}
+	if flip {
+		_ = b.FlipRange(0, n)
+	}
+}
+
+// checkProbability panics if p is not within [0, 1].
+func checkProbability(p float64) {
+	if !(p >= 0 && p <= 1) {
+		panic(fmt.Sprintf("randbits: probability %v is not within [0, 1]", p))
+	}
+}
+
+// Bitlist64 returns a bitlist of n bits, with bits set according to d.
+func (g *Generator) Bitlist64(n uint64, d Distribution) *bitfield.Bitlist64 {
+	b := bitfield.NewBitlist64(n)
+	d.fill(g.r, b)
+	return b
+}
+
+// Bitlist returns a bitlist of n bits, with bits set according to d.
+func (g *Generator) Bitlist(n uint64, d Distribution) bitfield.Bitlist {
+	return g.Bitlist64(n, d).ToBitlist()
+}
+
+// vector fills dst with bits set according to d.
+func (g *Generator) vector(dst bitfield.Bitfield, d Distribution) {
+	for _, idx := range g.Bitlist64(dst.Len(), d).BitIndices() {
+		dst.SetBitAt(uint64(idx), true)
+	}
+}
+
+// Bitvector4 returns a bitvector with bits set according to d.
+func (g *Generator) Bitvector4(d Distribution) bitfield.Bitvector4 {
+	b := bitfield.NewBitvector4()
+	g.vector(b, d)
+	return b
+}
+
+// Bitvector8 returns a bitvector with bits set according to d.
+func (g *Generator) Bitvector8(d Distribution) bitfield.Bitvector8 {
+	b := bitfield.NewBitvector8()
+	g.vector(b, d)
+	return b
+}
+
+// Bitvector32 returns a bitvector with bits set according to d.
+func (g *Generator) Bitvector32(d Distribution) bitfield.Bitvector32 {
+	b := bitfield.NewBitvector32()
+	g.vector(b, d)
+	return b
+}
+
+// Bitvector64 returns a bitvector with bits set according to d.
+func (g *Generator) Bitvector64(d Distribution) bitfield.Bitvector64 {
+	b := bitfield.NewBitvector64()
+	g.vector(b, d)
+	return b
+}
+
+// Bitvector128 returns a bitvector with bits set according to d.
+func (g *Generator) Bitvector128(d Distribution) bitfield.Bitvector128 {
+	b := bitfield.NewBitvector128()
+	g.vector(b, d)
+	return b
+}
+
+// Bitvector256 returns a bitvector with bits set according to d.
+func (g *Generator) Bitvector256(d Distribution) bitfield.Bitvector256 {
+	b := bitfield.NewBitvector256()
+	g.vector(b, d)
+	return b
+}
+
+// Bitvector512 returns a bitvector with bits set according to d.
+func (g *Generator) Bitvector512(d Distribution) bitfield.Bitvector512 {
+	b := bitfield.NewBitvector512()
+	g.vector(b, d)
+	return b
+}
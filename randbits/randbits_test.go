@@ -0,0 +1,133 @@
+package randbits
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGenerator_Reproducible(t *testing.T) {
+	for _, d := range []Distribution{Density(0.3), Clustered(0.5, 16), ExactCount(100)} {
+		a := NewSeeded(7).Bitlist64(1000, d)
+		b := NewSeeded(7).Bitlist64(1000, d)
+		if !a.Equal(*b) {
+			t.Errorf("%#v: same seed generated different bitlists", d)
+		}
+		if c := NewSeeded(8).Bitlist64(1000, d); a.Equal(*c) {
+			t.Errorf("%#v: different seeds generated the same bitlist", d)
+		}
+	}
+}
+
+func TestDensity(t *testing.T) {
+	g := NewSeeded(1)
+	n := uint64(100000)
+	for _, p := range []float64{0, 0.01, 0.5, 0.9, 1} {
+		got := float64(g.Bitlist64(n, Density(p)).Count()) / float64(n)
+		if math.Abs(got-p) > 0.01 {
+			t.Errorf("Density(%v) set a fraction %v of the bits", p, got)
+		}
+	}
+}
+
+func TestClustered(t *testing.T) {
+	g := NewSeeded(2)
+	n := uint64(200000)
+	for _, tt := range []struct{ p, meanRun float64 }{{0.5, 1}, {0.2, 8}, {0.9, 64}, {0, 8}, {1, 8}} {
+		b := g.Bitlist64(n, Clustered(tt.p, tt.meanRun))
+		if got := float64(b.Count()) / float64(n); math.Abs(got-tt.p) > 0.03 {
+			t.Errorf("Clustered(%v, %v) set a fraction %v of the bits", tt.p, tt.meanRun, got)
+		}
+		if tt.p == 0 || tt.p == 1 {
+			continue
+		}
+
+		// Count the runs of set bits to check their mean length.
+		runs := uint64(0)
+		for i := uint64(0); i < n; i++ {
+			if b.BitAt(i) && (i == 0 || !b.BitAt(i-1)) {
+				runs++
+			}
+		}
+		if got := float64(b.Count()) / float64(runs); math.Abs(got-tt.meanRun)/tt.meanRun > 0.1 {
+			t.Errorf("Clustered(%v, %v) set runs of mean length %v", tt.p, tt.meanRun, got)
+		}
+	}
+}
+
+func TestExactCount(t *testing.T) {
+	g := NewSeeded(3)
+	for _, n := range []uint64{0, 1, 10, 1000} {
+		for _, k := range []uint64{0, 1, n / 3, n / 2, n/2 + 1, n - 1, n} {
+			if k > n {
+				continue
+			}
+			if got := g.Bitlist64(n, ExactCount(k)).Count(); got != k {
+				t.Errorf("ExactCount(%d) of %d bits set %d bits", k, n, got)
+			}
+			if got := g.Bitlist(n, ExactCount(k)).Count(); got != k {
+				t.Errorf("ExactCount(%d) of %d bits set %d bits in a Bitlist", k, n, got)
+			}
+		}
+	}
+
+	// Every index is equally likely to be chosen.
+	hits := make([]int, 10)
+	for i := 0; i < 10000; i++ {
+		for _, idx := range g.Bitlist64(10, ExactCount(3)).BitIndices() {
+			hits[idx]++
+		}
+	}
+	for idx, h := range hits {
+		if h < 2700 || h > 3300 {
+			t.Errorf("index %d was chosen %d times, expected about 3000", idx, h)
+		}
+	}
+}
+
+func TestGenerator_Bitvectors(t *testing.T) {
+	g := NewSeeded(4)
+	if b := g.Bitvector4(ExactCount(4)); b.Count() != 4 || b[0] != 0x0F {
+		t.Errorf("Bitvector4(ExactCount(4)) = %x", b)
+	}
+	if b := g.Bitvector8(ExactCount(3)); b.Count() != 3 {
+		t.Errorf("Bitvector8(ExactCount(3)).Count() = %d", b.Count())
+	}
+	if b := g.Bitvector32(ExactCount(31)); b.Count() != 31 {
+		t.Errorf("Bitvector32(ExactCount(31)).Count() = %d", b.Count())
+	}
+	if b := g.Bitvector64(Density(1)); b.Count() != 64 {
+		t.Errorf("Bitvector64(Density(1)).Count() = %d", b.Count())
+	}
+	if b := g.Bitvector128(Density(0)); b.Count() != 0 {
+		t.Errorf("Bitvector128(Density(0)).Count() = %d", b.Count())
+	}
+	if b := g.Bitvector256(Clustered(1, 4)); b.Count() != 256 {
+		t.Errorf("Bitvector256(Clustered(1, 4)).Count() = %d", b.Count())
+	}
+	if b := g.Bitvector512(ExactCount(100)); b.Count() != 100 {
+		t.Errorf("Bitvector512(ExactCount(100)).Count() = %d", b.Count())
+	}
+}
+
+func TestDistribution_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{name: "negative density", f: func() { Density(-0.1) }},
+		{name: "NaN density", f: func() { Density(math.NaN()) }},
+		{name: "clustered density above 1", f: func() { Clustered(1.5, 2) }},
+		{name: "short runs", f: func() { Clustered(0.5, 0.5) }},
+		{name: "too many bits", f: func() { NewSeeded(0).Bitvector8(ExactCount(9)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			tt.f()
+		})
+	}
+}
@@ -0,0 +1,677 @@
+package bitfield
+
+import (
+	"math/bits"
+)
+
+// word is a storage unit of a bitfield: a byte for Bitlist and BitvectorN, a uint64 for Bitlist64.
+type word interface {
+	~uint8 | ~uint64
+}
+
+// wordBits returns the number of bits in a single word of type T.
+func wordBits[T word]() uint64 {
+	return uint64(bits.Len64(uint64(^T(0))))
+}
+
+// checkRange makes sure that [start, end) is a valid range within a bitfield of the given size.
+// The name of the calling method is used for error reporting.
+func checkRange(name string, start, end, size uint64) error {
+	if end > size {
+		return indexOutOfRange(name, end, size)
+	}
+	if start > end {
+		return invalidRange(name, start, end)
+	}
+	return nil
+}
+
+// checkVectorLen makes sure that the underlying byte slice of a bitvector has the expected size.
+// The name of the calling method is used for error reporting.
+func checkVectorLen(name string, b []byte, byteSize int) error {
+	if len(b) != byteSize {
+		return lengthMismatch(name, ErrWrongLen, uint64(len(b)), uint64(byteSize))
+	}
+	return nil
+}
+
+// checkVectorRange makes sure that the bitvector has the expected byte size, and that [start, end)
+// is a valid range within it. The name of the calling method is used for error reporting.
+func checkVectorRange(name string, b []byte, byteSize int, bitSize, start, end uint64) error {
+	if err := checkVectorLen(name, b, byteSize); err != nil {
+		return err
+	}
+	return checkRange(name, start, end, bitSize)
+}
+
+// rangeMasks returns indices of the first and the last words covering the non-empty [start, end)
+// range, together with masks selecting the in-range bits of those words. All the words in between
+// are fully covered by the range.
+func rangeMasks[T word](start, end uint64) (first, last uint64, firstMask, lastMask T) {
+	size := wordBits[T]()
+	first, last = start/size, (end-1)/size
+	firstMask = ^T(0) << (start % size)
+	lastMask = ^T(0) >> (size - 1 - (end-1)%size)
+	if first == last {
+		firstMask &= lastMask
+		lastMask = firstMask
+	}
+	return first, last, firstMask, lastMask
+}
+
+// setRange sets all bits in the [start, end) range.
+func setRange[T word](data []T, start, end uint64) {
+	if start >= end {
+		return
+	}
+	first, last, firstMask, lastMask := rangeMasks[T](start, end)
+	data[first] |= firstMask
+	for i := first + 1; i < last; i++ {
+		data[i] = ^T(0)
+	}
+	data[last] |= lastMask
+}
+
+// clearRange clears all bits in the [start, end) range.
+func clearRange[T word](data []T, start, end uint64) {
+	if start >= end {
+		return
+	}
+	first, last, firstMask, lastMask := rangeMasks[T](start, end)
+	data[first] &^= firstMask
+	for i := first + 1; i < last; i++ {
+		data[i] = 0
+	}
+	data[last] &^= lastMask
+}
+
+// flipRange inverts all bits in the [start, end) range.
+func flipRange[T word](data []T, start, end uint64) {
+	if start >= end {
+		return
+	}
+	first, last, firstMask, lastMask := rangeMasks[T](start, end)
+	if first == last {
+		data[first] ^= firstMask
+		return
+	}
+	data[first] ^= firstMask
+	for i := first + 1; i < last; i++ {
+		data[i] = ^data[i]
+	}
+	data[last] ^= lastMask
+}
+
+// countRange returns the number of bits set in the [start, end) range.
+func countRange[T word](data []T, start, end uint64) uint64 {
+	if start >= end {
+		return 0
+	}
+	first, last, firstMask, lastMask := rangeMasks[T](start, end)
+	if first == last {
+		return uint64(bits.OnesCount64(uint64(data[first] & firstMask)))
+	}
+	c := bits.OnesCount64(uint64(data[first] & firstMask))
+	for i := first + 1; i < last; i++ {
+		c += bits.OnesCount64(uint64(data[i]))
+	}
+	c += bits.OnesCount64(uint64(data[last] & lastMask))
+	return uint64(c)
+}
+
+// anyRange returns true if at least one bit in the [start, end) range is set.
+func anyRange[T word](data []T, start, end uint64) bool {
+	if start >= end {
+		return false
+	}
+	first, last, firstMask, lastMask := rangeMasks[T](start, end)
+	if data[first]&firstMask != 0 || data[last]&lastMask != 0 {
+		return true
+	}
+	for i := first + 1; i < last; i++ {
+		if data[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// allRange returns true if every bit in the [start, end) range is set. An empty range is
+// considered to be fully set.
+func allRange[T word](data []T, start, end uint64) bool {
+	if start >= end {
+		return true
+	}
+	first, last, firstMask, lastMask := rangeMasks[T](start, end)
+	if data[first]&firstMask != firstMask || data[last]&lastMask != lastMask {
+		return false
+	}
+	for i := first + 1; i < last; i++ {
+		if data[i] != ^T(0) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetRange sets all bits in the [start, end) range to 1.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b Bitlist) SetRange(start, end uint64) error {
+	if err := checkRange("Bitlist.SetRange", start, end, b.Len()); err != nil {
+		return err
+	}
+	setRange(b, start, end)
+	return nil
+}
+
+// ClearRange sets all bits in the [start, end) range to 0.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b Bitlist) ClearRange(start, end uint64) error {
+	if err := checkRange("Bitlist.ClearRange", start, end, b.Len()); err != nil {
+		return err
+	}
+	clearRange(b, start, end)
+	return nil
+}
+
+// FlipRange inverts all bits in the [start, end) range.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b Bitlist) FlipRange(start, end uint64) error {
+	if err := checkRange("Bitlist.FlipRange", start, end, b.Len()); err != nil {
+		return err
+	}
+	flipRange(b, start, end)
+	return nil
+}
+
+// CountRange returns the number of bits set to 1 in the [start, end) range.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b Bitlist) CountRange(start, end uint64) (uint64, error) {
+	if err := checkRange("Bitlist.CountRange", start, end, b.Len()); err != nil {
+		return 0, err
+	}
+	return countRange(b, start, end), nil
+}
+
+// AnyRange returns true if at least one bit in the [start, end) range is set to 1.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b Bitlist) AnyRange(start, end uint64) (bool, error) {
+	if err := checkRange("Bitlist.AnyRange", start, end, b.Len()); err != nil {
+		return false, err
+	}
+	return anyRange(b, start, end), nil
+}
+
+// AllRange returns true if all bits in the [start, end) range are set to 1. An empty range
+// is considered to be fully set.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b Bitlist) AllRange(start, end uint64) (bool, error) {
+	if err := checkRange("Bitlist.AllRange", start, end, b.Len()); err != nil {
+		return false, err
+	}
+	return allRange(b, start, end), nil
+}
+
+// SetRange sets all bits in the [start, end) range to 1.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b *Bitlist64) SetRange(start, end uint64) error {
+	if err := checkRange("Bitlist64.SetRange", start, end, b.size); err != nil {
+		return err
+	}
+	setRange(b.data, start, end)
+	return nil
+}
+
+// ClearRange sets all bits in the [start, end) range to 0.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b *Bitlist64) ClearRange(start, end uint64) error {
+	if err := checkRange("Bitlist64.ClearRange", start, end, b.size); err != nil {
+		return err
+	}
+	clearRange(b.data, start, end)
+	return nil
+}
+
+// FlipRange inverts all bits in the [start, end) range.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b *Bitlist64) FlipRange(start, end uint64) error {
+	if err := checkRange("Bitlist64.FlipRange", start, end, b.size); err != nil {
+		return err
+	}
+	flipRange(b.data, start, end)
+	return nil
+}
+
+// CountRange returns the number of bits set to 1 in the [start, end) range.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b *Bitlist64) CountRange(start, end uint64) (uint64, error) {
+	if err := checkRange("Bitlist64.CountRange", start, end, b.size); err != nil {
+		return 0, err
+	}
+	return countRange(b.data, start, end), nil
+}
+
+// AnyRange returns true if at least one bit in the [start, end) range is set to 1.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b *Bitlist64) AnyRange(start, end uint64) (bool, error) {
+	if err := checkRange("Bitlist64.AnyRange", start, end, b.size); err != nil {
+		return false, err
+	}
+	return anyRange(b.data, start, end), nil
+}
+
+// AllRange returns true if all bits in the [start, end) range are set to 1. An empty range
+// is considered to be fully set.
+// This method will return an error if the range is out of the bitlist bounds.
+func (b *Bitlist64) AllRange(start, end uint64) (bool, error) {
+	if err := checkRange("Bitlist64.AllRange", start, end, b.size); err != nil {
+		return false, err
+	}
+	return allRange(b.data, start, end), nil
+}
+
+// SetRange sets all bits in the [start, end) range to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector4) SetRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector4.SetRange", b, bitvector4ByteSize, bitvector4BitSize, start, end); err != nil {
+		return err
+	}
+	setRange(b, start, end)
+	return nil
+}
+
+// ClearRange sets all bits in the [start, end) range to 0.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector4) ClearRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector4.ClearRange", b, bitvector4ByteSize, bitvector4BitSize, start, end); err != nil {
+		return err
+	}
+	clearRange(b, start, end)
+	return nil
+}
+
+// FlipRange inverts all bits in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector4) FlipRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector4.FlipRange", b, bitvector4ByteSize, bitvector4BitSize, start, end); err != nil {
+		return err
+	}
+	flipRange(b, start, end)
+	return nil
+}
+
+// CountRange returns the number of bits set to 1 in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector4) CountRange(start, end uint64) (uint64, error) {
+	if err := checkVectorRange("Bitvector4.CountRange", b, bitvector4ByteSize, bitvector4BitSize, start, end); err != nil {
+		return 0, err
+	}
+	return countRange(b, start, end), nil
+}
+
+// AnyRange returns true if at least one bit in the [start, end) range is set to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector4) AnyRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector4.AnyRange", b, bitvector4ByteSize, bitvector4BitSize, start, end); err != nil {
+		return false, err
+	}
+	return anyRange(b, start, end), nil
+}
+
+// AllRange returns true if all bits in the [start, end) range are set to 1. An empty range
+// is considered to be fully set.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector4) AllRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector4.AllRange", b, bitvector4ByteSize, bitvector4BitSize, start, end); err != nil {
+		return false, err
+	}
+	return allRange(b, start, end), nil
+}
+
+// SetRange sets all bits in the [start, end) range to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector8) SetRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector8.SetRange", b, bitvector8ByteSize, bitvector8BitSize, start, end); err != nil {
+		return err
+	}
+	setRange(b, start, end)
+	return nil
+}
+
+// ClearRange sets all bits in the [start, end) range to 0.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector8) ClearRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector8.ClearRange", b, bitvector8ByteSize, bitvector8BitSize, start, end); err != nil {
+		return err
+	}
+	clearRange(b, start, end)
+	return nil
+}
+
+// FlipRange inverts all bits in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector8) FlipRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector8.FlipRange", b, bitvector8ByteSize, bitvector8BitSize, start, end); err != nil {
+		return err
+	}
+	flipRange(b, start, end)
+	return nil
+}
+
+// CountRange returns the number of bits set to 1 in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector8) CountRange(start, end uint64) (uint64, error) {
+	if err := checkVectorRange("Bitvector8.CountRange", b, bitvector8ByteSize, bitvector8BitSize, start, end); err != nil {
+		return 0, err
+	}
+	return countRange(b, start, end), nil
+}
+
+// AnyRange returns true if at least one bit in the [start, end) range is set to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector8) AnyRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector8.AnyRange", b, bitvector8ByteSize, bitvector8BitSize, start, end); err != nil {
+		return false, err
+	}
+	return anyRange(b, start, end), nil
+}
+
+// AllRange returns true if all bits in the [start, end) range are set to 1. An empty range
+// is considered to be fully set.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector8) AllRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector8.AllRange", b, bitvector8ByteSize, bitvector8BitSize, start, end); err != nil {
+		return false, err
+	}
+	return allRange(b, start, end), nil
+}
+
+// SetRange sets all bits in the [start, end) range to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector32) SetRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector32.SetRange", b, bitvector32ByteSize, bitvector32BitSize, start, end); err != nil {
+		return err
+	}
+	setRange(b, start, end)
+	return nil
+}
+
+// ClearRange sets all bits in the [start, end) range to 0.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector32) ClearRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector32.ClearRange", b, bitvector32ByteSize, bitvector32BitSize, start, end); err != nil {
+		return err
+	}
+	clearRange(b, start, end)
+	return nil
+}
+
+// FlipRange inverts all bits in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector32) FlipRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector32.FlipRange", b, bitvector32ByteSize, bitvector32BitSize, start, end); err != nil {
+		return err
+	}
+	flipRange(b, start, end)
+	return nil
+}
+
+// CountRange returns the number of bits set to 1 in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector32) CountRange(start, end uint64) (uint64, error) {
+	if err := checkVectorRange("Bitvector32.CountRange", b, bitvector32ByteSize, bitvector32BitSize, start, end); err != nil {
+		return 0, err
+	}
+	return countRange(b, start, end), nil
+}
+
+// AnyRange returns true if at least one bit in the [start, end) range is set to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector32) AnyRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector32.AnyRange", b, bitvector32ByteSize, bitvector32BitSize, start, end); err != nil {
+		return false, err
+	}
+	return anyRange(b, start, end), nil
+}
+
+// AllRange returns true if all bits in the [start, end) range are set to 1. An empty range
+// is considered to be fully set.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector32) AllRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector32.AllRange", b, bitvector32ByteSize, bitvector32BitSize, start, end); err != nil {
+		return false, err
+	}
+	return allRange(b, start, end), nil
+}
+
+// SetRange sets all bits in the [start, end) range to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector64) SetRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector64.SetRange", b, bitvector64ByteSize, bitvector64BitSize, start, end); err != nil {
+		return err
+	}
+	setRange(b, start, end)
+	return nil
+}
+
+// ClearRange sets all bits in the [start, end) range to 0.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector64) ClearRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector64.ClearRange", b, bitvector64ByteSize, bitvector64BitSize, start, end); err != nil {
+		return err
+	}
+	clearRange(b, start, end)
+	return nil
+}
+
+// FlipRange inverts all bits in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector64) FlipRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector64.FlipRange", b, bitvector64ByteSize, bitvector64BitSize, start, end); err != nil {
+		return err
+	}
+	flipRange(b, start, end)
+	return nil
+}
+
+// CountRange returns the number of bits set to 1 in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector64) CountRange(start, end uint64) (uint64, error) {
+	if err := checkVectorRange("Bitvector64.CountRange", b, bitvector64ByteSize, bitvector64BitSize, start, end); err != nil {
+		return 0, err
+	}
+	return countRange(b, start, end), nil
+}
+
+// AnyRange returns true if at least one bit in the [start, end) range is set to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector64) AnyRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector64.AnyRange", b, bitvector64ByteSize, bitvector64BitSize, start, end); err != nil {
+		return false, err
+	}
+	return anyRange(b, start, end), nil
+}
+
+// AllRange returns true if all bits in the [start, end) range are set to 1. An empty range
+// is considered to be fully set.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector64) AllRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector64.AllRange", b, bitvector64ByteSize, bitvector64BitSize, start, end); err != nil {
+		return false, err
+	}
+	return allRange(b, start, end), nil
+}
+
+// SetRange sets all bits in the [start, end) range to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector128) SetRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector128.SetRange", b, bitvector128ByteSize, bitvector128BitSize, start, end); err != nil {
+		return err
+	}
+	setRange(b, start, end)
+	return nil
+}
+
+// ClearRange sets all bits in the [start, end) range to 0.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector128) ClearRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector128.ClearRange", b, bitvector128ByteSize, bitvector128BitSize, start, end); err != nil {
+		return err
+	}
+	clearRange(b, start, end)
+	return nil
+}
+
+// FlipRange inverts all bits in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector128) FlipRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector128.FlipRange", b, bitvector128ByteSize, bitvector128BitSize, start, end); err != nil {
+		return err
+	}
+	flipRange(b, start, end)
+	return nil
+}
+
+// CountRange returns the number of bits set to 1 in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector128) CountRange(start, end uint64) (uint64, error) {
+	if err := checkVectorRange("Bitvector128.CountRange", b, bitvector128ByteSize, bitvector128BitSize, start, end); err != nil {
+		return 0, err
+	}
+	return countRange(b, start, end), nil
+}
+
+// AnyRange returns true if at least one bit in the [start, end) range is set to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector128) AnyRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector128.AnyRange", b, bitvector128ByteSize, bitvector128BitSize, start, end); err != nil {
+		return false, err
+	}
+	return anyRange(b, start, end), nil
+}
+
+// AllRange returns true if all bits in the [start, end) range are set to 1. An empty range
+// is considered to be fully set.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector128) AllRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector128.AllRange", b, bitvector128ByteSize, bitvector128BitSize, start, end); err != nil {
+		return false, err
+	}
+	return allRange(b, start, end), nil
+}
+
+// SetRange sets all bits in the [start, end) range to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector256) SetRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector256.SetRange", b, bitvector256ByteSize, bitvector256BitSize, start, end); err != nil {
+		return err
+	}
+	setRange(b, start, end)
+	return nil
+}
+
+// ClearRange sets all bits in the [start, end) range to 0.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector256) ClearRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector256.ClearRange", b, bitvector256ByteSize, bitvector256BitSize, start, end); err != nil {
+		return err
+	}
+	clearRange(b, start, end)
+	return nil
+}
+
+// FlipRange inverts all bits in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector256) FlipRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector256.FlipRange", b, bitvector256ByteSize, bitvector256BitSize, start, end); err != nil {
+		return err
+	}
+	flipRange(b, start, end)
+	return nil
+}
+
+// CountRange returns the number of bits set to 1 in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector256) CountRange(start, end uint64) (uint64, error) {
+	if err := checkVectorRange("Bitvector256.CountRange", b, bitvector256ByteSize, bitvector256BitSize, start, end); err != nil {
+		return 0, err
+	}
+	return countRange(b, start, end), nil
+}
+
+// AnyRange returns true if at least one bit in the [start, end) range is set to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector256) AnyRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector256.AnyRange", b, bitvector256ByteSize, bitvector256BitSize, start, end); err != nil {
+		return false, err
+	}
+	return anyRange(b, start, end), nil
+}
+
+// AllRange returns true if all bits in the [start, end) range are set to 1. An empty range
+// is considered to be fully set.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector256) AllRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector256.AllRange", b, bitvector256ByteSize, bitvector256BitSize, start, end); err != nil {
+		return false, err
+	}
+	return allRange(b, start, end), nil
+}
+
+// SetRange sets all bits in the [start, end) range to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector512) SetRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector512.SetRange", b, bitvector512ByteSize, bitvector512BitSize, start, end); err != nil {
+		return err
+	}
+	setRange(b, start, end)
+	return nil
+}
+
+// ClearRange sets all bits in the [start, end) range to 0.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector512) ClearRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector512.ClearRange", b, bitvector512ByteSize, bitvector512BitSize, start, end); err != nil {
+		return err
+	}
+	clearRange(b, start, end)
+	return nil
+}
+
+// FlipRange inverts all bits in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector512) FlipRange(start, end uint64) error {
+	if err := checkVectorRange("Bitvector512.FlipRange", b, bitvector512ByteSize, bitvector512BitSize, start, end); err != nil {
+		return err
+	}
+	flipRange(b, start, end)
+	return nil
+}
+
+// CountRange returns the number of bits set to 1 in the [start, end) range.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector512) CountRange(start, end uint64) (uint64, error) {
+	if err := checkVectorRange("Bitvector512.CountRange", b, bitvector512ByteSize, bitvector512BitSize, start, end); err != nil {
+		return 0, err
+	}
+	return countRange(b, start, end), nil
+}
+
+// AnyRange returns true if at least one bit in the [start, end) range is set to 1.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector512) AnyRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector512.AnyRange", b, bitvector512ByteSize, bitvector512BitSize, start, end); err != nil {
+		return false, err
+	}
+	return anyRange(b, start, end), nil
+}
+
+// AllRange returns true if all bits in the [start, end) range are set to 1. An empty range
+// is considered to be fully set.
+// This method will return an error if the range is out of the bitvector bounds.
+func (b Bitvector512) AllRange(start, end uint64) (bool, error) {
+	if err := checkVectorRange("Bitvector512.AllRange", b, bitvector512ByteSize, bitvector512BitSize, start, end); err != nil {
+		return false, err
+	}
+	return allRange(b, start, end), nil
+}
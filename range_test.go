@@ -0,0 +1,201 @@
+package bitfield
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type rangeBitfield interface {
+	Bitfield
+	SetRange(start, end uint64) error
+	ClearRange(start, end uint64) error
+	FlipRange(start, end uint64) error
+	CountRange(start, end uint64) (uint64, error)
+	AnyRange(start, end uint64) (bool, error)
+	AllRange(start, end uint64) (bool, error)
+}
+
+func rangeBitfields() map[string]func() rangeBitfield {
+	return map[string]func() rangeBitfield{
+		"Bitlist(0)":     func() rangeBitfield { return NewBitlist(0) },
+		"Bitlist(13)":    func() rangeBitfield { return NewBitlist(13) },
+		"Bitlist(300)":   func() rangeBitfield { return NewBitlist(300) },
+		"Bitlist64(0)":   func() rangeBitfield { return NewBitlist64(0) },
+		"Bitlist64(64)":  func() rangeBitfield { return NewBitlist64(64) },
+		"Bitlist64(300)": func() rangeBitfield { return NewBitlist64(300) },
+		"Bitvector4":     func() rangeBitfield { return NewBitvector4() },
+		"Bitvector8":     func() rangeBitfield { return NewBitvector8() },
+		"Bitvector32":    func() rangeBitfield { return NewBitvector32() },
+		"Bitvector64":    func() rangeBitfield { return NewBitvector64() },
+		"Bitvector128":   func() rangeBitfield { return NewBitvector128() },
+		"Bitvector256":   func() rangeBitfield { return NewBitvector256() },
+		"Bitvector512":   func() rangeBitfield { return NewBitvector512() },
+	}
+}
+
+// rangeBoundaries returns positions around byte and word boundaries that are within size.
+func rangeBoundaries(size uint64) []uint64 {
+	var ret []uint64
+	for _, p := range []uint64{0, 1, 3, 7, 8, 9, 15, 16, 31, 33, 63, 64, 65, 127, 129, 200, 255, 256, 299, 300, 511, 512} {
+		if p <= size {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// seedPattern sets every third bit, so that range operations have something to work against.
+func seedPattern(b Bitfield) {
+	for i := uint64(0); i < b.Len(); i += 3 {
+		b.SetBitAt(i, true)
+	}
+}
+
+func TestRange_Operations(t *testing.T) {
+	for name, newBitfield := range rangeBitfields() {
+		size := newBitfield().Len()
+		for _, start := range rangeBoundaries(size) {
+			for _, end := range rangeBoundaries(size) {
+				if start > end {
+					continue
+				}
+				t.Run(fmt.Sprintf("%s[%d:%d]", name, start, end), func(t *testing.T) {
+					inRange := func(i uint64) bool { return i >= start && i < end }
+
+					ops := []struct {
+						name  string
+						apply func(b rangeBitfield) error
+						want  func(i uint64, old bool) bool
+					}{
+						{
+							name:  "SetRange",
+							apply: func(b rangeBitfield) error { return b.SetRange(start, end) },
+							want:  func(i uint64, old bool) bool { return old || inRange(i) },
+						},
+						{
+							name:  "ClearRange",
+							apply: func(b rangeBitfield) error { return b.ClearRange(start, end) },
+							want:  func(i uint64, old bool) bool { return old && !inRange(i) },
+						},
+						{
+							name:  "FlipRange",
+							apply: func(b rangeBitfield) error { return b.FlipRange(start, end) },
+							want:  func(i uint64, old bool) bool { return old != inRange(i) },
+						},
+					}
+					for _, op := range ops {
+						b := newBitfield()
+						seedPattern(b)
+						if err := op.apply(b); err != nil {
+							t.Fatalf("%s(%d, %d) returned error: %v", op.name, start, end, err)
+						}
+						for i := uint64(0); i < size; i++ {
+							if got, want := b.BitAt(i), op.want(i, i%3 == 0); got != want {
+								t.Fatalf("%s(%d, %d): BitAt(%d) = %t, wanted %t", op.name, start, end, i, got, want)
+							}
+						}
+						if b.Len() != size {
+							t.Fatalf("%s(%d, %d) changed length to %d", op.name, start, end, b.Len())
+						}
+					}
+
+					b := newBitfield()
+					seedPattern(b)
+					wantCount := uint64(0)
+					for i := start; i < end; i++ {
+						if i%3 == 0 {
+							wantCount++
+						}
+					}
+					if got, err := b.CountRange(start, end); got != wantCount || err != nil {
+						t.Errorf("CountRange(%d, %d) = %d, %v, wanted %d", start, end, got, err, wantCount)
+					}
+					if got, err := b.AnyRange(start, end); got != (wantCount > 0) || err != nil {
+						t.Errorf("AnyRange(%d, %d) = %t, %v, wanted %t", start, end, got, err, wantCount > 0)
+					}
+					if got, err := b.AllRange(start, end); got != (wantCount == end-start) || err != nil {
+						t.Errorf("AllRange(%d, %d) = %t, %v, wanted %t", start, end, got, err, wantCount == end-start)
+					}
+					if err := b.SetRange(start, end); err != nil {
+						t.Fatal(err)
+					}
+					if got, err := b.AllRange(start, end); !got || err != nil {
+						t.Errorf("AllRange(%d, %d) after SetRange() = %t, %v, wanted true", start, end, got, err)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestRange_Bitlist(t *testing.T) {
+	tests := []struct {
+		bitlist Bitlist
+		start   uint64
+		end     uint64
+		want    Bitlist
+	}{
+		{
+			bitlist: Bitlist{0x20}, // 0b00100000
+			start:   1,
+			end:     4,
+			want:    Bitlist{0x2E}, // 0b00101110
+		},
+		{
+			bitlist: Bitlist{0x00, 0x00, 0x01},
+			start:   6,
+			end:     12,
+			want:    Bitlist{0xC0, 0x0F, 0x01},
+		},
+		{
+			bitlist: Bitlist{0x00, 0x00, 0x01},
+			start:   0,
+			end:     16,
+			want:    Bitlist{0xFF, 0xFF, 0x01},
+		},
+	}
+
+	for _, tt := range tests {
+		if err := tt.bitlist.SetRange(tt.start, tt.end); err != nil || !bytes.Equal(tt.bitlist, tt.want) {
+			t.Errorf("SetRange(%d, %d) = %#x, %v, wanted %#x", tt.start, tt.end, tt.bitlist, err, tt.want)
+		}
+	}
+}
+
+func TestRange_Errors(t *testing.T) {
+	for name, newBitfield := range rangeBitfields() {
+		t.Run(name, func(t *testing.T) {
+			b := newBitfield()
+			size := b.Len()
+			for _, r := range [][2]uint64{{0, size + 1}, {size + 1, size + 2}, {1, 0}} {
+				if err := b.SetRange(r[0], r[1]); !errors.Is(err, ErrIndexOutOfRange) {
+					t.Errorf("SetRange(%d, %d): wanted %v, got %v", r[0], r[1], ErrIndexOutOfRange, err)
+				}
+				if err := b.ClearRange(r[0], r[1]); !errors.Is(err, ErrIndexOutOfRange) {
+					t.Errorf("ClearRange(%d, %d): wanted %v, got %v", r[0], r[1], ErrIndexOutOfRange, err)
+				}
+				if err := b.FlipRange(r[0], r[1]); !errors.Is(err, ErrIndexOutOfRange) {
+					t.Errorf("FlipRange(%d, %d): wanted %v, got %v", r[0], r[1], ErrIndexOutOfRange, err)
+				}
+				if _, err := b.CountRange(r[0], r[1]); !errors.Is(err, ErrIndexOutOfRange) {
+					t.Errorf("CountRange(%d, %d): wanted %v, got %v", r[0], r[1], ErrIndexOutOfRange, err)
+				}
+				if _, err := b.AnyRange(r[0], r[1]); !errors.Is(err, ErrIndexOutOfRange) {
+					t.Errorf("AnyRange(%d, %d): wanted %v, got %v", r[0], r[1], ErrIndexOutOfRange, err)
+				}
+				if _, err := b.AllRange(r[0], r[1]); !errors.Is(err, ErrIndexOutOfRange) {
+					t.Errorf("AllRange(%d, %d): wanted %v, got %v", r[0], r[1], ErrIndexOutOfRange, err)
+				}
+			}
+		})
+	}
+
+	t.Run("wrong bitvector length", func(t *testing.T) {
+		b := Bitvector64{0x00, 0x00}
+		if err := b.SetRange(0, 1); !errors.Is(err, ErrWrongLen) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package bitfield
+
+import (
+	"strconv"
+	"strings"
+)
+
+// defaultRenderWidth is the number of bits per row used when no width is given.
+const defaultRenderWidth = 64
+
+// renderGroupSize is the number of bits between two spaces in a rendered row.
+const renderGroupSize = 8
+
+// Renderer draws bitfields as a grid of rows, each prefixed by the index of its first bit, under a
+// ruler of column offsets. Bits are grouped by 8 for readability. For example, a Bitvector32
+// rendered 16 bits per row with ASCII glyphs:
+//
+//	    0        8
+//	 0  ####.... ........
+//	16  ........ ....####
+type Renderer struct {
+	// Width is the number of bits per row, defaults to 64.
+	Width int
+	// ASCII selects # and . for set and unset bits, instead of █ and ░.
+	ASCII bool
+	// Against is an optional bitfield of the same length to compare with. Bits set only in the
+	// rendered bitfield are drawn as +, and bits set only in Against are drawn as -.
+	Against Bitfield
+}
+
+// Render draws the bitfield as rows of width bits, using █ for set bits and ░ for unset bits.
+// See Renderer for details.
+func Render(b Bitfield, width int) string {
+	// A Renderer without Against can't fail.
+	ret, _ := Renderer{Width: width}.Render(b)
+	return ret
+}
+
+// Render draws the bitfield as a grid. An empty bitfield is rendered as an empty string.
+// This method will return an error if Against is set and has a different length.
+func (r Renderer) Render(b Bitfield) (string, error) {
+	if r.Against != nil && r.Against.Len() != b.Len() {
+		return "", lengthMismatch("Renderer.Render", ErrBitfieldDifferentLength, b.Len(), r.Against.Len())
+	}
+	size := b.Len()
+	if size == 0 {
+		return "", nil
+	}
+	width := uint64(r.Width)
+	if r.Width <= 0 {
+		width = defaultRenderWidth
+	}
+	set, unset := "█", "░"
+	if r.ASCII {
+		set, unset = "#", "."
+	}
+
+	lastRow := (size - 1) / width * width
+	labelWidth := len(strconv.FormatUint(lastRow, 10))
+	var sb strings.Builder
+
+	// Ruler with the offset of each group of bits in a row.
+	sb.WriteString(strings.Repeat(" ", labelWidth+2))
+	columns := width
+	if size < columns {
+		columns = size
+	}
+	var ruler strings.Builder
+	for col := uint64(0); col < columns; col += renderGroupSize {
+		label := strconv.FormatUint(col, 10)
+		ruler.WriteString(label)
+		if pad := renderGroupSize + 1 - len(label); pad > 0 {
+			ruler.WriteString(strings.Repeat(" ", pad))
+		}
+	}
+	sb.WriteString(strings.TrimRight(ruler.String(), " "))
+	sb.WriteByte('\n')
+
+	for start := uint64(0); start < size; start += width {
+		label := strconv.FormatUint(start, 10)
+		sb.WriteString(strings.Repeat(" ", labelWidth-len(label)))
+		sb.WriteString(label)
+		sb.WriteString("  ")
+		for i := start; i < start+width && i < size; i++ {
+			if i > start && (i-start)%renderGroupSize == 0 {
+				sb.WriteByte(' ')
+			}
+			bit := b.BitAt(i)
+			switch {
+			case r.Against != nil && bit && !r.Against.BitAt(i):
+				sb.WriteByte('+')
+			case r.Against != nil && !bit && r.Against.BitAt(i):
+				sb.WriteByte('-')
+			case bit:
+				sb.WriteString(set)
+			default:
+				sb.WriteString(unset)
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,94 @@
+package bitfield
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	b := NewBitvector32()
+	for _, i := range []uint64{0, 1, 2, 3, 28, 29, 30, 31} {
+		b.SetBitAt(i, true)
+	}
+
+	tests := []struct {
+		name     string
+		b        Bitfield
+		renderer Renderer
+		want     string
+	}{
+		{
+			name:     "ascii",
+			b:        b,
+			renderer: Renderer{Width: 16, ASCII: true},
+			want: "" +
+				"    0        8\n" +
+				" 0  ####.... ........\n" +
+				"16  ........ ....####\n",
+		},
+		{
+			name:     "unicode",
+			b:        Bitlist{0x05, 0x02},
+			renderer: Renderer{Width: 4},
+			want: "" +
+				"   0\n" +
+				"0  █░█░\n" +
+				"4  ░░░░\n" +
+				"8  ░\n",
+		},
+		{
+			name:     "default width",
+			b:        NewBitlist64From([]uint64{0x8000000000000001, 0x01}),
+			renderer: Renderer{ASCII: true},
+			want: "" +
+				"    0        8        16       24       32       40       48       56\n" +
+				" 0  #....... ........ ........ ........ ........ ........ ........ .......#\n" +
+				"64  #....... ........ ........ ........ ........ ........ ........ ........\n",
+		},
+		{
+			name:     "diff",
+			b:        Bitvector8{0b00110101},
+			renderer: Renderer{ASCII: true, Against: Bitvector8{0b01010110}},
+			want: "" +
+				"   0\n" +
+				"0  +-#.#+-.\n",
+		},
+		{
+			name:     "bitvector4 ignores unused bits",
+			b:        Bitvector4{0xF1},
+			renderer: Renderer{ASCII: true},
+			want: "" +
+				"   0\n" +
+				"0  #...\n",
+		},
+		{
+			name:     "empty",
+			b:        NewBitlist(0),
+			renderer: Renderer{},
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.renderer.Render(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() =\n%s\nwanted\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := Render(Bitvector8{0x81}, 8), "   0\n0  █░░░░░░█\n"; got != want {
+		t.Errorf("Render() = %q, wanted %q", got, want)
+	}
+}
+
+func TestRender_DifferentLength(t *testing.T) {
+	_, err := Renderer{Against: NewBitvector4()}.Render(NewBitvector8())
+	if !errors.Is(err, ErrBitfieldDifferentLength) {
+		t.Errorf("Render() error = %v, wanted %v", err, ErrBitfieldDifferentLength)
+	}
+}
@@ -0,0 +1,74 @@
+package bitfield
+
+// Scatter projects a committee-local bitlist onto a registry-wide bitlist: the bit i of src is
+// written to the bit indices[i] of dst, for both set and unset bits. It returns the number of bits
+// in dst which were changed from 0 to 1.
+// This method will return an error if the number of indices does not match the length of src, or
+// if any of the indices is out of the dst bounds. In both cases dst is left untouched.
+func Scatter(dst *Bitlist64, src Bitlist, indices []uint64) (uint64, error) {
+	return scatter("Scatter", dst, src, indices, false)
+}
+
+// ScatterOr is similar to Scatter, but only ORs the set bits of src into dst, leaving the bits
+// mapped to unset bits of src intact. It returns the number of bits in dst which were changed
+// from 0 to 1.
+// This method will return an error if the number of indices does not match the length of src, or
+// if any of the indices is out of the dst bounds. In both cases dst is left untouched.
+func ScatterOr(dst *Bitlist64, src Bitlist, indices []uint64) (uint64, error) {
+	return scatter("ScatterOr", dst, src, indices, true)
+}
+
+// Gather is the inverse of Scatter: it collects the bits indices[i] of the registry-wide bitlist
+// src into the bit i of a new committee-local bitlist.
+// This method will return an error if any of the indices is out of the src bounds.
+func Gather(src *Bitlist64, indices []uint64) (Bitlist, error) {
+	if err := checkIndices("Gather", indices, src.Len()); err != nil {
+		return nil, err
+	}
+
+	ret := NewBitlist(uint64(len(indices)))
+	for i, idx := range indices {
+		if src.data[idx>>wordSizeLog2]&(1<<(idx%wordSize)) != 0 {
+			ret[i/8] |= 1 << (i % 8)
+		}
+	}
+
+	return ret, nil
+}
+
+func scatter(name string, dst *Bitlist64, src Bitlist, indices []uint64, orOnly bool) (uint64, error) {
+	if uint64(len(indices)) != src.Len() {
+		return 0, lengthMismatch(name, ErrIndicesWrongLength, src.Len(), uint64(len(indices)))
+	}
+	// Validate all the indices upfront, so that dst is never partially updated.
+	if err := checkIndices(name, indices, dst.Len()); err != nil {
+		return 0, err
+	}
+
+	newlySet := uint64(0)
+	for i, idx := range indices {
+		word := &dst.data[idx>>wordSizeLog2]
+		mask := uint64(1) << (idx % wordSize)
+		if src[i/8]&(1<<(i%8)) != 0 {
+			if *word&mask == 0 {
+				newlySet++
+				*word |= mask
+			}
+		} else if !orOnly {
+			*word &^= mask
+		}
+	}
+
+	return newlySet, nil
+}
+
+// checkIndices makes sure that all the indices are within a bitfield of the given size.
+// The name of the calling method is used for error reporting.
+func checkIndices(name string, indices []uint64, size uint64) error {
+	for _, idx := range indices {
+		if idx >= size {
+			return indexOutOfRange(name, idx, size)
+		}
+	}
+	return nil
+}
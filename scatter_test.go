@@ -0,0 +1,87 @@
+package bitfield
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestScatter(t *testing.T) {
+	indices := []uint64{70, 3, 64, 127, 0}
+	src := NewBitlist(5)
+	src.SetBitAt(0, true) // -> 70
+	src.SetBitAt(2, true) // -> 64
+	src.SetBitAt(4, true) // -> 0
+
+	newRegistry := func() *Bitlist64 {
+		b := NewBitlist64(128)
+		b.SetBitAt(0, true)   // already set, mapped from a set bit
+		b.SetBitAt(3, true)   // already set, mapped from an unset bit
+		b.SetBitAt(100, true) // not mapped
+		return b
+	}
+
+	t.Run("Scatter()", func(t *testing.T) {
+		dst := newRegistry()
+		n, err := Scatter(dst, src, indices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Errorf("Scatter() newly set = %d, wanted 2", n)
+		}
+		if want := []int{0, 64, 70, 100}; !reflect.DeepEqual(dst.BitIndices(), want) {
+			t.Errorf("Scatter() = %v, wanted %v", dst.BitIndices(), want)
+		}
+	})
+
+	t.Run("ScatterOr()", func(t *testing.T) {
+		dst := newRegistry()
+		n, err := ScatterOr(dst, src, indices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Errorf("ScatterOr() newly set = %d, wanted 2", n)
+		}
+		if want := []int{0, 3, 64, 70, 100}; !reflect.DeepEqual(dst.BitIndices(), want) {
+			t.Errorf("ScatterOr() = %v, wanted %v", dst.BitIndices(), want)
+		}
+	})
+
+	t.Run("Gather()", func(t *testing.T) {
+		dst := newRegistry()
+		if _, err := Scatter(dst, src, indices); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Gather(dst, indices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, src) {
+			t.Errorf("Gather(Scatter(%#x)) = %#x", src, got)
+		}
+	})
+
+	t.Run("check errors", func(t *testing.T) {
+		dst := newRegistry()
+		want := dst.Clone()
+		if _, err := Scatter(dst, src, indices[:4]); !errors.Is(err, ErrIndicesWrongLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndicesWrongLength, err)
+		}
+		outOfRange := []uint64{70, 3, 64, 128, 0}
+		if _, err := Scatter(dst, src, outOfRange); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+		}
+		if _, err := ScatterOr(dst, src, outOfRange); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+		}
+		if !reflect.DeepEqual(dst, want) {
+			t.Errorf("Failed Scatter() modified destination: %v, wanted %v", dst.BitIndices(), want.BitIndices())
+		}
+		if _, err := Gather(dst, outOfRange); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package bitfield
+
+import (
+	"slices"
+)
+
+var _ = Bitfield(&SparseBitlist{})
+
+const (
+	// sparseDensifyRatio switches a sparse bitlist to the dense representation once more than
+	// 1 in sparseDensifyRatio bits are set, which is where a sorted list of uint64 indices becomes
+	// larger than the bits themselves.
+	sparseDensifyRatio = 64
+	// sparseSparsifyRatio switches a dense bitlist back to the sparse representation once fewer than
+	// 1 in sparseSparsifyRatio bits are set. It is lower than the densify threshold, so that a
+	// bitlist close to the threshold doesn't keep switching back and forth.
+	sparseSparsifyRatio = 128
+)
+
+// SparseBitlist is a bitlist that only stores the sorted indices of its set bits while few of them
+// are set. Once the density of set bits passes a threshold it switches to a dense Bitlist64, and it
+// switches back once enough bits are cleared. This keeps bitlists over huge index spaces with only
+// a few bits set, such as committee participation, both small and fast.
+type SparseBitlist struct {
+	size    uint64
+	count   uint64
+	indices []uint64
+	// dense holds the bits when the bitlist is in the dense representation, and is nil otherwise.
+	dense *Bitlist64
+}
+
+// NewSparseBitlist creates a new sparse bitlist of size `n`, with all bits unset.
+func NewSparseBitlist(n uint64) *SparseBitlist {
+	return &SparseBitlist{size: n, indices: []uint64{}}
+}
+
+// NewSparseBitlistFrom creates a new sparse bitlist holding the same bits as b, in whichever
+// representation suits its density. The bits are copied.
+func NewSparseBitlistFrom(b *Bitlist64) *SparseBitlist {
+	return newSparseBitlistFromDense(b.Clone())
+}
+
+// newSparseBitlistFromDense creates a sparse bitlist taking ownership of b.
+func newSparseBitlistFromDense(b *Bitlist64) *SparseBitlist {
+	ret := &SparseBitlist{size: b.size, count: b.Count(), dense: b}
+	ret.normalize()
+	return ret
+}
+
+// newSparseBitlistFromIndices creates a sparse bitlist taking ownership of sorted indices.
+func newSparseBitlistFromIndices(size uint64, indices []uint64) *SparseBitlist {
+	ret := &SparseBitlist{size: size, count: uint64(len(indices)), indices: indices}
+	ret.normalize()
+	return ret
+}
+
+// IsDense returns true if the bitlist currently uses the dense representation.
+func (b *SparseBitlist) IsDense() bool {
+	return b.dense != nil
+}
+
+// BitAt returns the bit value at the given index. If the index requested
+// exceeds the number of bits in the bitlist, then this method returns false.
+func (b *SparseBitlist) BitAt(idx uint64) bool {
+	if b.dense != nil {
+		return b.dense.BitAt(idx)
+	}
+	_, found := slices.BinarySearch(b.indices, idx)
+	return found
+}
+
+// SetBitAt sets the bit at the given index to val. The representation is switched if the density
+// crosses a threshold.
+//
+// If the index requested exceeds the number of bits in the bitlist, then this method does nothing.
+func (b *SparseBitlist) SetBitAt(idx uint64, val bool) {
+	if idx >= b.size || b.BitAt(idx) == val {
+		return
+	}
+
+	if b.dense != nil {
+		b.dense.SetBitAt(idx, val)
+	} else {
+		i, _ := slices.BinarySearch(b.indices, idx)
+		if val {
+			b.indices = slices.Insert(b.indices, i, idx)
+		} else {
+			b.indices = slices.Delete(b.indices, i, i+1)
+		}
+	}
+	if val {
+		b.count++
+	} else {
+		b.count--
+	}
+	b.normalize()
+}
+
+// Len returns the number of bits in the bitlist.
+func (b *SparseBitlist) Len() uint64 {
+	return b.size
+}
+
+// Count returns the number of 1s in the bitlist.
+func (b *SparseBitlist) Count() uint64 {
+	return b.count
+}
+
+// Bytes returns the bytes value of the bitlist, without the length bit. Leading zeros are trimmed
+// in the same way as for Bitlist64.
+func (b *SparseBitlist) Bytes() []byte {
+	return b.ToBitlist64().Bytes()
+}
+
+// BitIndices returns list of bit indexes of bitlist where value is set to true.
+func (b *SparseBitlist) BitIndices() []int {
+	if b.dense != nil {
+		return b.dense.BitIndices()
+	}
+	ret := make([]int, len(b.indices))
+	for i, idx := range b.indices {
+		ret[i] = int(idx)
+	}
+	return ret
+}
+
+// ToBitlist64 returns a dense copy of the bitlist.
+func (b *SparseBitlist) ToBitlist64() *Bitlist64 {
+	if b.dense != nil {
+		return b.dense.Clone()
+	}
+	ret := NewBitlist64(b.size)
+	for _, idx := range b.indices {
+		ret.SetBitAt(idx, true)
+	}
+	return ret
+}
+
+// Or returns the OR result of the two bitlists. Two sparse bitlists are merged without switching to
+// the dense representation.
+// This method will return an error if the bitlists are of different lengths.
+func (b *SparseBitlist) Or(c *SparseBitlist) (*SparseBitlist, error) {
+	return b.combine("SparseBitlist.Or", c, true, true, true, (*Bitlist64).Or)
+}
+
+// And returns the AND result of the two bitlists. Two sparse bitlists are merged without switching
+// to the dense representation.
+// This method will return an error if the bitlists are of different lengths.
+func (b *SparseBitlist) And(c *SparseBitlist) (*SparseBitlist, error) {
+	return b.combine("SparseBitlist.And", c, false, false, true, (*Bitlist64).And)
+}
+
+// Xor returns the XOR result of the two bitlists. Two sparse bitlists are merged without switching
+// to the dense representation.
+// This method will return an error if the bitlists are of different lengths.
+func (b *SparseBitlist) Xor(c *SparseBitlist) (*SparseBitlist, error) {
+	return b.combine("SparseBitlist.Xor", c, true, true, false, (*Bitlist64).Xor)
+}
+
+// Contains returns true if the bitlist contains all of the bits from the provided argument
+// bitlist i.e. if `b` is a superset of `c`.
+// This method will return an error if the bitlists are of different lengths.
+func (b *SparseBitlist) Contains(c *SparseBitlist) (bool, error) {
+	and, err := b.And(c)
+	if err != nil {
+		return false, err
+	}
+	return and.count == c.count, nil
+}
+
+// Overlaps returns true if the bitlist contains one of the bits from the provided argument
+// bitlist.
+// This method will return an error if the bitlists are of different lengths.
+func (b *SparseBitlist) Overlaps(c *SparseBitlist) (bool, error) {
+	and, err := b.And(c)
+	if err != nil {
+		return false, err
+	}
+	return and.count != 0, nil
+}
+
+// combine applies a set operation to the two bitlists. When both are sparse, their indices are
+// merged, keeping indices only in b if onlyB, only in c if onlyC, and in both if both. Otherwise,
+// the dense operation op is applied.
+func (b *SparseBitlist) combine(
+	name string,
+	c *SparseBitlist,
+	onlyB, onlyC, both bool,
+	op func(b, c *Bitlist64) (*Bitlist64, error),
+) (*SparseBitlist, error) {
+	if b.size != c.size {
+		return nil, lengthMismatch(name, ErrBitlistDifferentLength, b.size, c.size)
+	}
+
+	if b.dense != nil || c.dense != nil {
+		ret, err := op(b.asBitlist64(), c.asBitlist64())
+		if err != nil {
+			return nil, err
+		}
+		return newSparseBitlistFromDense(ret), nil
+	}
+
+	ret := make([]uint64, 0, len(b.indices)+len(c.indices))
+	i, j := 0, 0
+	for i < len(b.indices) || j < len(c.indices) {
+		switch {
+		case j == len(c.indices) || (i < len(b.indices) && b.indices[i] < c.indices[j]):
+			if onlyB {
+				ret = append(ret, b.indices[i])
+			}
+			i++
+		case i == len(b.indices) || c.indices[j] < b.indices[i]:
+			if onlyC {
+				ret = append(ret, c.indices[j])
+			}
+			j++
+		default:
+			if both {
+				ret = append(ret, b.indices[i])
+			}
+			i++
+			j++
+		}
+	}
+	return newSparseBitlistFromIndices(b.size, ret), nil
+}
+
+// asBitlist64 returns the dense bits of the bitlist, without copying them if they are already
+// dense. The result must not be modified.
+func (b *SparseBitlist) asBitlist64() *Bitlist64 {
+	if b.dense != nil {
+		return b.dense
+	}
+	return b.ToBitlist64()
+}
+
+// normalize switches the representation if the density crossed a threshold.
+func (b *SparseBitlist) normalize() {
+	switch {
+	case b.dense == nil && b.count*sparseDensifyRatio > b.size:
+		b.dense = b.ToBitlist64()
+		b.indices = nil
+	case b.dense != nil && b.count*sparseSparsifyRatio < b.size:
+		indices := make([]uint64, 0, b.count)
+		for _, idx := range b.dense.BitIndices() {
+			indices = append(indices, uint64(idx))
+		}
+		b.indices, b.dense = indices, nil
+	}
+}
@@ -0,0 +1,169 @@
+package bitfield
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSparseBitlist_SetBitAt(t *testing.T) {
+	size := uint64(6400)
+	b := NewSparseBitlist(size)
+	ref := NewBitlist64(size)
+
+	// Fill up to the densify threshold, then past it, then clear back to the sparsify threshold.
+	steps := []struct {
+		set       bool
+		upTo      uint64
+		wantDense bool
+	}{
+		{set: true, upTo: 100, wantDense: false},
+		{set: true, upTo: 101, wantDense: true},
+		{set: true, upTo: 3000, wantDense: true},
+		{set: false, upTo: 50, wantDense: true},
+		{set: false, upTo: 49, wantDense: false},
+		{set: false, upTo: 0, wantDense: false},
+	}
+	count := uint64(0)
+	for _, step := range steps {
+		for count != step.upTo {
+			if step.set {
+				b.SetBitAt(count*2, true)
+				ref.SetBitAt(count*2, true)
+				count++
+			} else {
+				count--
+				b.SetBitAt(count*2, false)
+				ref.SetBitAt(count*2, false)
+			}
+		}
+		if b.IsDense() != step.wantDense {
+			t.Errorf("with %d bits set IsDense() = %t, wanted %t", count, b.IsDense(), step.wantDense)
+		}
+		if b.Count() != ref.Count() {
+			t.Errorf("Count() = %d, wanted %d", b.Count(), ref.Count())
+		}
+		if !reflect.DeepEqual(b.BitIndices(), ref.BitIndices()) {
+			t.Errorf("with %d bits set BitIndices() differ", count)
+		}
+		if !reflect.DeepEqual(b.Bytes(), ref.Bytes()) {
+			t.Errorf("with %d bits set Bytes() differ", count)
+		}
+		for _, idx := range []uint64{0, 1, 198, 200, size - 1, size} {
+			if b.BitAt(idx) != ref.BitAt(idx) {
+				t.Errorf("with %d bits set BitAt(%d) = %t, wanted %t", count, idx, b.BitAt(idx), ref.BitAt(idx))
+			}
+		}
+	}
+
+	// Setting a bit to its current value, or out of range, does nothing.
+	b.SetBitAt(size, true)
+	b.SetBitAt(1, false)
+	if b.Count() != 0 {
+		t.Errorf("Count() = %d, wanted 0", b.Count())
+	}
+}
+
+func TestSparseBitlist_SetAlgebra(t *testing.T) {
+	size := uint64(10000)
+	sparse := func(indices ...uint64) *SparseBitlist {
+		b := NewSparseBitlist(size)
+		for _, idx := range indices {
+			b.SetBitAt(idx, true)
+		}
+		return b
+	}
+	dense := func(start, end uint64) *SparseBitlist {
+		b := NewBitlist64(size)
+		if err := b.SetRange(start, end); err != nil {
+			t.Fatal(err)
+		}
+		return NewSparseBitlistFrom(b)
+	}
+
+	inputs := map[string]*SparseBitlist{
+		"sparse a": sparse(1, 5, 9000),
+		"sparse b": sparse(5, 6, 9999),
+		"dense a":  dense(0, 1000),
+		"dense b":  dense(500, 2000),
+		"empty":    sparse(),
+	}
+	if inputs["sparse a"].IsDense() || !inputs["dense a"].IsDense() {
+		t.Fatal("inputs are not in the expected representation")
+	}
+
+	ops := []struct {
+		name  string
+		op    func(b, c *SparseBitlist) (*SparseBitlist, error)
+		refOp func(b, c *Bitlist64) (*Bitlist64, error)
+	}{
+		{name: "Or", op: (*SparseBitlist).Or, refOp: (*Bitlist64).Or},
+		{name: "And", op: (*SparseBitlist).And, refOp: (*Bitlist64).And},
+		{name: "Xor", op: (*SparseBitlist).Xor, refOp: (*Bitlist64).Xor},
+	}
+	for bName, b := range inputs {
+		for cName, c := range inputs {
+			rb, rc := b.ToBitlist64(), c.ToBitlist64()
+			for _, op := range ops {
+				got, err := op.op(b, c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, err := op.refOp(rb, rc)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !got.ToBitlist64().Equal(*want) || got.Count() != want.Count() {
+					t.Errorf("%s %s %s = %v, wanted %v", bName, op.name, cName, got.BitIndices(), want.BitIndices())
+				}
+				if wantDense := want.Count()*sparseDensifyRatio > size; got.IsDense() != wantDense &&
+					want.Count()*sparseSparsifyRatio >= size {
+					t.Errorf("%s %s %s: IsDense() = %t, wanted %t", bName, op.name, cName, got.IsDense(), wantDense)
+				}
+			}
+
+			contains, err := b.Contains(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantContains, err := rb.Contains(rc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contains != wantContains {
+				t.Errorf("%s Contains %s = %t, wanted %t", bName, cName, contains, wantContains)
+			}
+			overlaps, err := b.Overlaps(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantOverlaps, err := rb.Overlaps(rc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if overlaps != wantOverlaps {
+				t.Errorf("%s Overlaps %s = %t, wanted %t", bName, cName, overlaps, wantOverlaps)
+			}
+		}
+	}
+
+	if _, err := sparse(1).Or(NewSparseBitlist(size + 1)); !errors.Is(err, ErrBitlistDifferentLength) {
+		t.Errorf("Or() error = %v, wanted %v", err, ErrBitlistDifferentLength)
+	}
+}
+
+func TestSparseBitlist_Conversions(t *testing.T) {
+	b := NewBitlist64(128)
+	b.SetBitAt(3, true)
+	s := NewSparseBitlistFrom(b)
+	// The bits are copied.
+	b.SetBitAt(4, true)
+	if s.BitAt(4) {
+		t.Error("NewSparseBitlistFrom() shares the bits of its argument")
+	}
+	d := s.ToBitlist64()
+	d.SetBitAt(5, true)
+	if s.BitAt(5) {
+		t.Error("ToBitlist64() shares the bits of the sparse bitlist")
+	}
+}
@@ -0,0 +1,130 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+var (
+	_ io.WriterTo   = &Bitlist64{}
+	_ io.ReaderFrom = &Bitlist64{}
+)
+
+// streamBufferSize is the size of the buffer used by WriteTo and ReadFrom, which bounds the memory
+// used on top of the bitlist itself.
+const streamBufferSize = 4096
+
+// WriteTo writes the bitlist to w in the SSZ encoding of a bitlist, i.e. the same bytes as
+// ToBitlist, without building them in memory first. The bytes are written in chunks of at most
+// 4 KiB. It returns the number of bytes written.
+func (b *Bitlist64) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	buf := make([]byte, 0, streamBufferSize)
+	flush := func() error {
+		n, err := w.Write(buf)
+		written += int64(n)
+		buf = buf[:0]
+		return err
+	}
+
+	// Bytes fully covered by the bits, the remaining bits share the last byte with the length bit.
+	fullBytes := b.size / 8
+	var word [bytesInWord]byte
+	for i := uint64(0); i < fullBytes; i += bytesInWord {
+		binary.LittleEndian.PutUint64(word[:], b.data[i>>bytesInWordLog2])
+		buf = append(buf, word[:min(bytesInWord, int(fullBytes-i))]...)
+		if len(buf)+bytesInWord > cap(buf) {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+
+	last := uint8(1 << (b.size % 8))
+	if b.size%8 != 0 {
+		last |= b.byteAt(fullBytes) & (uint8(1<<(b.size%8)) - 1)
+	}
+	buf = append(buf, last)
+	return written, flush()
+}
+
+// ReadFrom reads a bitlist in the SSZ encoding of a bitlist from r until EOF, such as the output of
+// WriteTo, and replaces the bitlist with it, whatever its previous length. The bytes are read in
+// chunks of at most 4 KiB, and converted to words as they arrive. It returns the number of bytes
+// read. Use ReadFromExpected to bound the length of the stream.
+// This method will return an error if the stream is empty or does not end with a length bit. The
+// bitlist is left unchanged if an error is returned.
+func (b *Bitlist64) ReadFrom(r io.Reader) (int64, error) {
+	return b.readFrom("Bitlist64.ReadFrom", r, 0, false)
+}
+
+// ReadFromExpected is like ReadFrom, but the stream must hold a bitlist of n bits. Reading stops
+// as soon as the stream is longer than the n/8+1 bytes of such a bitlist, so a bogus stream can't
+// make it read or allocate more than that.
+// This method will return an error if the stream is empty, does not end with a length bit, or does
+// not hold n bits. The bitlist is left unchanged if an error is returned.
+func (b *Bitlist64) ReadFromExpected(r io.Reader, n uint64) (int64, error) {
+	return b.readFrom("Bitlist64.ReadFromExpected", r, n, true)
+}
+
+// readFrom implements ReadFrom and ReadFromExpected. If expected is set, the stream must hold a
+// bitlist of n bits. The name of the calling method is used for error reporting.
+func (b *Bitlist64) readFrom(name string, r io.Reader, n uint64, expected bool) (int64, error) {
+	var read int64
+	words := make([]uint64, 0, numWordsRequired(n))
+	maxBytes := int64(n/8 + 1)
+	if expected {
+		// One extra byte is enough to tell that the stream is too long.
+		r = io.LimitReader(r, maxBytes+1)
+	}
+	buf := make([]byte, streamBufferSize)
+	// pending holds the bytes not yet converted to words. The last byte read is always held back,
+	// since the length bit is only known once the stream ends.
+	pending := make([]byte, 0, streamBufferSize+2*bytesInWord)
+	for {
+		k, err := r.Read(buf)
+		read += int64(k)
+		if expected && read > maxBytes {
+			// The bytes read so far hold at least 8*(read-1) bits, which is more than n.
+			return read, lengthMismatch(name, ErrBitlistDifferentLength, uint64(read-1)*8, n)
+		}
+		pending = append(pending, buf[:k]...)
+		i := 0
+		for ; len(pending)-i > bytesInWord; i += bytesInWord {
+			words = append(words, binary.LittleEndian.Uint64(pending[i:]))
+		}
+		pending = pending[:copy(pending, pending[i:])]
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return read, err
+		}
+	}
+
+	if len(pending) == 0 {
+		return read, fmt.Errorf("%s: %w: empty stream", name, ErrInvalidEncoding)
+	}
+	last := pending[len(pending)-1]
+	if last == 0 {
+		return read, fmt.Errorf("%s: %w: missing length bit", name, ErrInvalidEncoding)
+	}
+	size := uint64(len(words))*wordSize + uint64(len(pending)-1)*8 + uint64(bits.Len8(last)) - 1
+	if expected && size != n {
+		return read, lengthMismatch(name, ErrBitlistDifferentLength, size, n)
+	}
+
+	// Convert the remaining bytes, without the length bit, into the last word.
+	if size > uint64(len(words))*wordSize {
+		var word [bytesInWord]byte
+		copy(word[:], pending)
+		word[len(pending)-1] &^= uint8(1 << (bits.Len8(last) - 1))
+		words = append(words, binary.LittleEndian.Uint64(word[:]))
+	}
+	b.size, b.data = size, words
+	return read, nil
+}
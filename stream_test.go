@@ -0,0 +1,174 @@
+package bitfield
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBitlist64_WriteTo(t *testing.T) {
+	for _, size := range []uint64{0, 1, 7, 8, 9, 63, 64, 65, 4095 * 8, 4096 * 8, 4096*8 + 1, 100000} {
+		b := NewBitlist64(size)
+		for i := uint64(0); i < size; i += 3 {
+			b.SetBitAt(i, true)
+		}
+
+		var buf bytes.Buffer
+		n, err := b.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []byte(b.ToBitlist())
+		if !bytes.Equal(buf.Bytes(), want) || n != int64(len(want)) {
+			t.Errorf("size %d: WriteTo() wrote %d bytes %x, wanted %x", size, n, buf.Bytes(), want)
+		}
+
+		// Read it back, one byte at a time to exercise the held back bytes.
+		got := &Bitlist64{}
+		n, err = got.ReadFrom(iotest.OneByteReader(bytes.NewReader(want)))
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if n != int64(len(want)) || got.Len() != size || !got.Equal(*b) {
+			t.Errorf("size %d: ReadFrom() read %d bytes into %v, wanted %v", size, n, got, b)
+		}
+
+		// The expected length is enforced.
+		sized := &Bitlist64{}
+		if _, err := sized.ReadFromExpected(bytes.NewReader(want), size); err != nil || !sized.Equal(*b) {
+			t.Errorf("size %d: ReadFromExpected() = %v, %v", size, sized, err)
+		}
+		for _, expected := range []uint64{size + 1, size + 8, size + 64} {
+			wrong := NewBitlist64From([]uint64{0x01})
+			if _, err := wrong.ReadFromExpected(bytes.NewReader(want), expected); !errors.Is(err, ErrBitlistDifferentLength) {
+				t.Errorf("size %d: ReadFromExpected(%d) error = %v, wanted %v", size, expected, err, ErrBitlistDifferentLength)
+			}
+			if wrong.Len() != 64 || wrong.Count() != 1 {
+				t.Errorf("size %d: ReadFromExpected() modified the bitlist on error", size)
+			}
+		}
+	}
+}
+
+func TestBitlist64_ReadFrom_Reuse(t *testing.T) {
+	// ReadFrom always resets the bitlist, whatever the length of the previous stream.
+	b := &Bitlist64{}
+	for _, size := range []uint64{100, 7, 300, 0, 64} {
+		want := NewBitlist64(size)
+		for i := uint64(0); i < size; i += 2 {
+			want.SetBitAt(i, true)
+		}
+		if _, err := b.ReadFrom(bytes.NewReader(want.ToBitlist())); err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if !b.Equal(*want) {
+			t.Errorf("size %d: ReadFrom() = %v, wanted %v", size, b, want)
+		}
+	}
+}
+
+// countingReader reads an endless stream of 0xFF bytes, and counts them.
+type countingReader struct {
+	read int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0xFF
+	}
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func TestBitlist64_ReadFromExpected_StopsEarly(t *testing.T) {
+	for _, size := range []uint64{0, 8, 100, 100000} {
+		r := &countingReader{}
+		b := &Bitlist64{}
+		n, err := b.ReadFromExpected(r, size)
+		if !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("size %d: ReadFromExpected() error = %v, wanted %v", size, err, ErrBitlistDifferentLength)
+		}
+		if limit := int64(size/8 + 2); n > limit || r.read > limit {
+			t.Errorf("size %d: ReadFromExpected() read %d bytes (%d from the stream), wanted at most %d", size, n, r.read, limit)
+		}
+		if b.Len() != 0 {
+			t.Errorf("size %d: ReadFromExpected() modified the bitlist on error", size)
+		}
+	}
+}
+
+func TestBitlist64_StreamThroughCompression(t *testing.T) {
+	b := NewBitlist64(1 << 20)
+	if err := b.SetRange(1000, 500000); err != nil {
+		t.Fatal(err)
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := b.WriteTo(zw); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := gzip.NewReader(&compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &Bitlist64{}
+	if _, err = got.ReadFromExpected(zr, 1<<20); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(*b) {
+		t.Error("bitlist changed after a round trip through gzip")
+	}
+}
+
+func TestBitlist64_ReadFrom_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		r    io.Reader
+		want error
+	}{
+		{name: "empty", r: bytes.NewReader(nil), want: ErrInvalidEncoding},
+		{name: "missing length bit", r: bytes.NewReader([]byte{0xFF, 0x00}), want: ErrInvalidEncoding},
+		{name: "read error", r: iotest.TimeoutReader(bytes.NewReader(bytes.Repeat([]byte{0xFF}, 8192))), want: iotest.ErrTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBitlist64From([]uint64{0x01})
+			if _, err := b.ReadFrom(tt.r); !errors.Is(err, tt.want) {
+				t.Errorf("ReadFrom() error = %v, wanted %v", err, tt.want)
+			}
+			if b.Len() != 64 || b.Count() != 1 {
+				t.Error("ReadFrom() modified the bitlist on error")
+			}
+		})
+	}
+}
+
+type failingWriter struct {
+	after int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.after < len(p) {
+		n := w.after
+		w.after = 0
+		return n, io.ErrShortWrite
+	}
+	w.after -= len(p)
+	return len(p), nil
+}
+
+func TestBitlist64_WriteTo_Error(t *testing.T) {
+	b := NewBitlist64(100000)
+	n, err := b.WriteTo(&failingWriter{after: 5000})
+	if !errors.Is(err, io.ErrShortWrite) || n != 5000 {
+		t.Errorf("WriteTo() = %d, %v, wanted 5000, %v", n, err, io.ErrShortWrite)
+	}
+}
@@ -0,0 +1,124 @@
+package bitfield
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SubnetBitvector is the set of bitvectors advertised in ENR and metadata fields: Bitvector64 for
+// the attestation subnets (attnets), and Bitvector4 for the sync committee subnets (syncnets).
+type SubnetBitvector interface {
+	Bitvector4 | Bitvector64
+	Bitfield
+}
+
+// Subnets is a set of gossip subnets, backed by a bitvector where the bit i stands for the
+// subnet with ID i.
+type Subnets[V SubnetBitvector] struct {
+	bits V
+}
+
+// AttestationSubnets is the set of attestation subnets (the attnets field).
+type AttestationSubnets = Subnets[Bitvector64]
+
+// SyncSubnets is the set of sync committee subnets (the syncnets field).
+type SyncSubnets = Subnets[Bitvector4]
+
+// NewSubnets creates a set of subnets from a copy of the given bitvector.
+// This method will return an error if the bitvector is not of the correct length.
+func NewSubnets[V SubnetBitvector](bits V) (Subnets[V], error) {
+	if err := checkVectorLen("NewSubnets", bits, subnetByteSize[V]()); err != nil {
+		return Subnets[V]{}, err
+	}
+	ret := emptySubnets[V]()
+	copy(ret.bits, bits)
+	return ret, nil
+}
+
+// FromSubnetIDs creates a set holding the given subnets.
+// This method will return an error if any of the subnet IDs is out of range.
+func FromSubnetIDs[V SubnetBitvector](ids ...uint64) (Subnets[V], error) {
+	ret := emptySubnets[V]()
+	for _, id := range ids {
+		if id >= ret.bits.Len() {
+			return Subnets[V]{}, indexOutOfRange("FromSubnetIDs", id, ret.bits.Len())
+		}
+		ret.bits.SetBitAt(id, true)
+	}
+	return ret, nil
+}
+
+// Bitvector returns the bitvector backing the set of subnets.
+func (s Subnets[V]) Bitvector() V {
+	if s.bits == nil {
+		return emptySubnets[V]().bits
+	}
+	return s.bits
+}
+
+// SubnetIDs returns the IDs of the subnets in the set, in increasing order.
+func (s Subnets[V]) SubnetIDs() []uint64 {
+	indices := s.Bitvector().BitIndices()
+	ids := make([]uint64, 0, len(indices))
+	for _, idx := range indices {
+		// Bits above the bitvector length may be present in the last byte (e.g. in Bitvector4).
+		if uint64(idx) >= s.Bitvector().Len() {
+			break
+		}
+		ids = append(ids, uint64(idx))
+	}
+	return ids
+}
+
+// Has returns true if the subnet with the given ID is in the set.
+func (s Subnets[V]) Has(id uint64) bool {
+	return s.Bitvector().BitAt(id)
+}
+
+// Union returns the set of subnets that are in either of the two sets.
+func (s Subnets[V]) Union(other Subnets[V]) Subnets[V] {
+	ret := emptySubnets[V]()
+	for _, id := range s.SubnetIDs() {
+		ret.bits.SetBitAt(id, true)
+	}
+	for _, id := range other.SubnetIDs() {
+		ret.bits.SetBitAt(id, true)
+	}
+	return ret
+}
+
+// Missing returns the IDs of the required subnets that are not in the set, in increasing order.
+func (s Subnets[V]) Missing(required Subnets[V]) []uint64 {
+	missing := make([]uint64, 0)
+	for _, id := range required.SubnetIDs() {
+		if !s.Has(id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// String returns the subnet IDs of the set in increasing order, e.g. "[0,5,63]".
+func (s Subnets[V]) String() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i, id := range s.SubnetIDs() {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatUint(id, 10))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// emptySubnets creates a set with no subnets.
+func emptySubnets[V SubnetBitvector]() Subnets[V] {
+	return Subnets[V]{bits: V(make([]byte, subnetByteSize[V]()))}
+}
+
+// subnetByteSize returns the number of bytes in a bitvector of type V.
+func subnetByteSize[V SubnetBitvector]() int {
+	var v V
+	return int((v.Len() + 7) / 8)
+}
@@ -0,0 +1,88 @@
+package bitfield
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAttestationSubnets(t *testing.T) {
+	s, err := FromSubnetIDs[Bitvector64](63, 5, 0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{0, 5, 63}; !reflect.DeepEqual(s.SubnetIDs(), want) {
+		t.Errorf("SubnetIDs() = %v, wanted %v", s.SubnetIDs(), want)
+	}
+	if want := (Bitvector64{0x21, 0, 0, 0, 0, 0, 0, 0x80}); !bytes.Equal(s.Bitvector(), want) {
+		t.Errorf("Bitvector() = %#x, wanted %#x", s.Bitvector(), want)
+	}
+	if got, want := s.String(), "[0,5,63]"; got != want {
+		t.Errorf("String() = %q, wanted %q", got, want)
+	}
+	if !s.Has(5) || s.Has(6) || s.Has(64) {
+		t.Errorf("Has() returned unexpected results for %v", s)
+	}
+
+	other, err := FromSubnetIDs[Bitvector64](1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{0, 1, 5, 63}; !reflect.DeepEqual(s.Union(other).SubnetIDs(), want) {
+		t.Errorf("Union() = %v, wanted %v", s.Union(other), want)
+	}
+	if want := []uint64{1}; !reflect.DeepEqual(s.Missing(other), want) {
+		t.Errorf("Missing() = %v, wanted %v", s.Missing(other), want)
+	}
+	if want := []uint64{}; !reflect.DeepEqual(s.Union(other).Missing(s), want) {
+		t.Errorf("Missing() = %v, wanted %v", s.Union(other).Missing(s), want)
+	}
+
+	if _, err := FromSubnetIDs[Bitvector64](64); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+	}
+}
+
+func TestSyncSubnets(t *testing.T) {
+	// Upper bits of the byte are not part of the bitvector.
+	s, err := NewSubnets(Bitvector4{0xF4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{2}; !reflect.DeepEqual(s.SubnetIDs(), want) {
+		t.Errorf("SubnetIDs() = %v, wanted %v", s.SubnetIDs(), want)
+	}
+	if got, want := s.String(), "[2]"; got != want {
+		t.Errorf("String() = %q, wanted %q", got, want)
+	}
+
+	var empty SyncSubnets
+	if got, want := empty.String(), "[]"; got != want {
+		t.Errorf("String() = %q, wanted %q", got, want)
+	}
+	if want := []uint64{2}; !reflect.DeepEqual(empty.Missing(s), want) {
+		t.Errorf("Missing() = %v, wanted %v", empty.Missing(s), want)
+	}
+	if !bytes.Equal(empty.Bitvector(), NewBitvector4()) {
+		t.Errorf("Bitvector() = %#x, wanted %#x", empty.Bitvector(), NewBitvector4())
+	}
+
+	if _, err = FromSubnetIDs[Bitvector4](4); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+	}
+	if _, err = NewSubnets(Bitvector4{0x01, 0x00}); !errors.Is(err, ErrWrongLen) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+	}
+
+	// The set holds its own copy of the bitvector.
+	bits := NewBitvector4()
+	s, err = NewSubnets(bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits.SetBitAt(1, true)
+	if s.Has(1) {
+		t.Errorf("NewSubnets() did not copy the bitvector")
+	}
+}
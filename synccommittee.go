@@ -0,0 +1,66 @@
+package bitfield
+
+// SyncCommitteeSubnetCount is the number of subcommittees the sync committee is split into. Every
+// subcommittee covers a Bitvector128 sized slice of the Bitvector512 sync aggregate bits.
+const SyncCommitteeSubnetCount = bitvector512BitSize / bitvector128BitSize
+
+// SplitSyncAggregate splits the sync committee bits of a sync aggregate into the bits of its
+// subcommittees, indexed by subcommittee index.
+// This method will return an error if the sync committee bits are not of the correct length.
+func SplitSyncAggregate(syncCommitteeBits Bitvector512) ([]Bitvector128, error) {
+	if err := checkVectorLen("SplitSyncAggregate", syncCommitteeBits, bitvector512ByteSize); err != nil {
+		return nil, err
+	}
+
+	ret := make([]Bitvector128, SyncCommitteeSubnetCount)
+	for i := range ret {
+		ret[i] = NewBitvector128()
+		copy(ret[i], syncCommitteeBits[i*bitvector128ByteSize:(i+1)*bitvector128ByteSize])
+	}
+	return ret, nil
+}
+
+// AssembleSyncAggregate builds the sync committee bits of a sync aggregate from the bits of its
+// subcommittees, indexed by subcommittee index. Empty (or nil) entries leave the bits of their
+// subcommittee unset.
+// This method will return an error if there are too many subcommittees, or if any of them is not
+// of the correct length.
+func AssembleSyncAggregate(subcommittees []Bitvector128) (Bitvector512, error) {
+	if len(subcommittees) > SyncCommitteeSubnetCount {
+		return nil, indexOutOfRange("AssembleSyncAggregate", uint64(len(subcommittees))-1, SyncCommitteeSubnetCount)
+	}
+
+	ret := NewBitvector512()
+	for i, subcommittee := range subcommittees {
+		if len(subcommittee) == 0 {
+			continue
+		}
+		if err := OrSyncContribution(ret, uint64(i), subcommittee); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// OrSyncContribution merges the aggregation bits of a sync committee contribution into the sync
+// committee bits of a sync aggregate, using an OR. Contributions for the same subcommittee can be
+// merged one after another.
+// This method will return an error if the subcommittee index is out of range, or if any of the
+// bitvectors is not of the correct length.
+func OrSyncContribution(syncCommitteeBits Bitvector512, subcommitteeIndex uint64, aggregationBits Bitvector128) error {
+	if err := checkVectorLen("OrSyncContribution", syncCommitteeBits, bitvector512ByteSize); err != nil {
+		return err
+	}
+	if err := checkVectorLen("OrSyncContribution", aggregationBits, bitvector128ByteSize); err != nil {
+		return err
+	}
+	if subcommitteeIndex >= SyncCommitteeSubnetCount {
+		return indexOutOfRange("OrSyncContribution", subcommitteeIndex, SyncCommitteeSubnetCount)
+	}
+
+	offset := int(subcommitteeIndex) * bitvector128ByteSize
+	for i, bt := range aggregationBits {
+		syncCommitteeBits[offset+i] |= bt
+	}
+	return nil
+}
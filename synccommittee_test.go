@@ -0,0 +1,81 @@
+package bitfield
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSyncAggregate_SplitAssemble(t *testing.T) {
+	aggregate := NewBitvector512()
+	for _, idx := range []uint64{0, 127, 128, 200, 383, 511} {
+		aggregate.SetBitAt(idx, true)
+	}
+
+	subcommittees, err := SplitSyncAggregate(aggregate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]int{{0, 127}, {0, 72}, {127}, {127}}
+	for i, subcommittee := range subcommittees {
+		if !reflect.DeepEqual(subcommittee.BitIndices(), want[i]) {
+			t.Errorf("subcommittee %d = %v, wanted %v", i, subcommittee.BitIndices(), want[i])
+		}
+	}
+
+	assembled, err := AssembleSyncAggregate(subcommittees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(assembled, aggregate) {
+		t.Errorf("AssembleSyncAggregate(SplitSyncAggregate(%#x)) = %#x", aggregate, assembled)
+	}
+
+	t.Run("missing subcommittees", func(t *testing.T) {
+		got, err := AssembleSyncAggregate([]Bitvector128{nil, subcommittees[1]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []int{128, 200}; !reflect.DeepEqual(got.BitIndices(), want) {
+			t.Errorf("AssembleSyncAggregate() = %v, wanted %v", got.BitIndices(), want)
+		}
+	})
+
+	t.Run("check errors", func(t *testing.T) {
+		if _, err := SplitSyncAggregate(Bitvector512{0x01}); !errors.Is(err, ErrWrongLen) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+		}
+		if _, err := AssembleSyncAggregate(make([]Bitvector128, 5)); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+		}
+		if _, err := AssembleSyncAggregate([]Bitvector128{{0x01}}); !errors.Is(err, ErrWrongLen) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+		}
+	})
+}
+
+func TestOrSyncContribution(t *testing.T) {
+	aggregate := NewBitvector512()
+	first, second := NewBitvector128(), NewBitvector128()
+	first.SetBitAt(1, true)
+	second.SetBitAt(1, true)
+	second.SetBitAt(100, true)
+
+	if err := OrSyncContribution(aggregate, 2, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := OrSyncContribution(aggregate, 2, second); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{257, 356}; !reflect.DeepEqual(aggregate.BitIndices(), want) {
+		t.Errorf("OrSyncContribution() = %v, wanted %v", aggregate.BitIndices(), want)
+	}
+
+	if err := OrSyncContribution(aggregate, 4, first); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrIndexOutOfRange, err)
+	}
+	if err := OrSyncContribution(aggregate, 0, Bitvector128{0x01}); !errors.Is(err, ErrWrongLen) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+	}
+}
@@ -0,0 +1,112 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// nativeLittleEndian is true if the target stores words in little-endian byte order, which is the
+// order bitlists are serialized in.
+var nativeLittleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+
+// Bitlist64View returns a read-only Bitlist64 view of the bitlist, without copying its bits. The
+// view aliases the bytes of the bitlist: changes to the bitlist are visible through the view.
+//
+// It returns false if the bitlist cannot be viewed in place, in which case ToBitlist64 should be
+// used instead. A view is only possible on little-endian targets, when the underlying array is
+// 8-byte aligned, and when the length of the bitlist is a multiple of 64 bits, so that the length
+// bit lies past the last word. Most committee sizes are not a multiple of 64, so callers must always
+// be ready for the copy.
+func (b Bitlist) Bitlist64View() (*ReadOnlyBitlist64, bool) {
+	size := b.Len()
+	if !nativeLittleEndian || len(b) == 0 || size%wordSize != 0 {
+		return nil, false
+	}
+	if size == 0 {
+		return NewReadOnlyBitlist64(NewBitlist64(0)), true
+	}
+	ptr := unsafe.Pointer(unsafe.SliceData(b))
+	if uintptr(ptr)%unsafe.Alignof(uint64(0)) != 0 {
+		return nil, false
+	}
+
+	return NewReadOnlyBitlist64(&Bitlist64{
+		size: size,
+		data: unsafe.Slice((*uint64)(ptr), size>>wordSizeLog2),
+	}), true
+}
+
+// ReadOnlyBitlist64 is a read-only view of a Bitlist64, for bits that must not be modified or
+// resized through the view, such as the ones of a Bitlist64View or of a read-only MmapBitlist64.
+// Changes to the underlying bitlist are visible through the view. Use Clone to get a Bitlist64
+// that can be modified.
+//
+// ReadOnlyBitlist64 implements the read-only methods of Bitfield.
+type ReadOnlyBitlist64 struct {
+	b *Bitlist64
+}
+
+// NewReadOnlyBitlist64 returns a read-only view of b.
+func NewReadOnlyBitlist64(b *Bitlist64) *ReadOnlyBitlist64 {
+	return &ReadOnlyBitlist64{b: b}
+}
+
+// BitAt returns the bit value at the given index. If the index requested
+// exceeds the number of bits in the bitlist, then this method returns false.
+func (v *ReadOnlyBitlist64) BitAt(idx uint64) bool {
+	return v.b.BitAt(idx)
+}
+
+// Len returns the number of bits in the bitlist.
+func (v *ReadOnlyBitlist64) Len() uint64 {
+	return v.b.Len()
+}
+
+// Count returns the number of 1s in the bitlist.
+func (v *ReadOnlyBitlist64) Count() uint64 {
+	return v.b.Count()
+}
+
+// Bytes returns the bytes value of the bitlist, without the length bit. Leading zeros are trimmed
+// in the same way as for Bitlist64.
+func (v *ReadOnlyBitlist64) Bytes() []byte {
+	return v.b.Bytes()
+}
+
+// BitIndices returns list of bit indexes of bitlist where value is set to true.
+func (v *ReadOnlyBitlist64) BitIndices() []int {
+	return v.b.BitIndices()
+}
+
+// CountRange returns the number of 1s in the [start, end) range of the bitlist.
+// This method will return an error if the range is out of the bitlist bounds.
+func (v *ReadOnlyBitlist64) CountRange(start, end uint64) (uint64, error) {
+	return v.b.CountRange(start, end)
+}
+
+// Equal returns true if the view and c have the same length, and hold the same bits.
+func (v *ReadOnlyBitlist64) Equal(c Bitlist64) bool {
+	return v.b.Equal(c)
+}
+
+// Hash returns a 64-bit non-cryptographic hash of the bitlist, equal to the one of a Bitlist64
+// holding the same bits.
+func (v *ReadOnlyBitlist64) Hash() uint64 {
+	return v.b.Hash()
+}
+
+// Key returns a string that uniquely identifies the length and the bits of the bitlist, equal to
+// the one of a Bitlist64 holding the same bits.
+func (v *ReadOnlyBitlist64) Key() string {
+	return v.b.Key()
+}
+
+// ToBitlist returns a copy of the bits as a []byte backed bitlist.
+func (v *ReadOnlyBitlist64) ToBitlist() Bitlist {
+	return v.b.ToBitlist()
+}
+
+// Clone returns a copy of the bits as a Bitlist64, which does not share memory with the view.
+func (v *ReadOnlyBitlist64) Clone() *Bitlist64 {
+	return v.b.Clone()
+}
@@ -0,0 +1,59 @@
+package bitfield
+
+import (
+	"testing"
+)
+
+func TestBitlist_Bitlist64View(t *testing.T) {
+	if !nativeLittleEndian {
+		t.Skip("views require a little-endian target")
+	}
+
+	tests := []struct {
+		name    string
+		bitlist Bitlist
+		ok      bool
+	}{
+		{name: "empty", bitlist: NewBitlist(0), ok: true},
+		{name: "one word", bitlist: NewBitlist(64), ok: true},
+		{name: "many words", bitlist: NewBitlist(1024), ok: true},
+		{name: "partial word", bitlist: NewBitlist(65), ok: false},
+		{name: "partial byte", bitlist: NewBitlist(5), ok: false},
+		{name: "no length bit", bitlist: Bitlist{}, ok: false},
+		{name: "unaligned", bitlist: append(make(Bitlist, 1, 10), NewBitlist(64)...)[1:], ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := uint64(0); i < tt.bitlist.Len(); i += 7 {
+				tt.bitlist.SetBitAt(i, true)
+			}
+			view, ok := tt.bitlist.Bitlist64View()
+			if ok != tt.ok {
+				t.Fatalf("Bitlist64View() ok = %v, wanted %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if !EqualBitlists(tt.bitlist, view.Clone()) || view.Len() != tt.bitlist.Len() || view.Count() != tt.bitlist.Count() {
+				t.Errorf("Bitlist64View() = %+v, wanted the bits of %#x", view.Clone(), []byte(tt.bitlist))
+			}
+			if view.Key() != tt.bitlist.Key() || view.Hash() != tt.bitlist.Hash() {
+				t.Errorf("Bitlist64View() key and hash differ from the ones of %#x", []byte(tt.bitlist))
+			}
+			if tt.bitlist.Len() == 0 {
+				return
+			}
+
+			// Changes to the bitlist show through the view, but not through a clone.
+			clone := view.Clone()
+			tt.bitlist.SetBitAt(1, true)
+			if !view.BitAt(1) {
+				t.Error("Bitlist64View() does not share memory with the bitlist")
+			}
+			clone.SetBitAt(2, true)
+			if tt.bitlist.BitAt(2) {
+				t.Error("Clone() shares memory with the bitlist")
+			}
+		})
+	}
+}
@@ -0,0 +1,402 @@
+package bitfield
+
+import (
+	"fmt"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// weightedCount returns the sum of weights[i] for every bit i set in the first size bits of a, or
+// of op(a, b) when op is provided. Zero words are skipped without looking at individual bits. The
+// name of the calling method is used for error reporting.
+func weightedCount[T word](name string, a, b []T, op func(x, y T) T, size uint64, weights []uint64) (uint64, error) {
+	if uint64(len(weights)) != size {
+		return 0, lengthMismatch(name, ErrWeightsWrongLength, size, uint64(len(weights)))
+	}
+
+	w := wordBits[T]()
+	var sum, carry uint64
+	for i := range a {
+		x := a[i]
+		if op != nil {
+			x = op(x, b[i])
+		}
+		base := uint64(i) * w
+		for x != 0 {
+			idx := base + uint64(bits.TrailingZeros64(uint64(x)))
+			if idx >= size {
+				// Only the length bit or unused bits are left in the last word.
+				break
+			}
+			sum, carry = bits.Add64(sum, weights[idx], 0)
+			if carry != 0 {
+				return 0, fmt.Errorf("%s: %w at index %d", name, ErrWeightOverflow, idx)
+			}
+			// Clear the lowest set bit.
+			x &= x - 1
+		}
+	}
+	return sum, nil
+}
+
+func and[T word](x, y T) T { return x & y }
+
+func or[T word](x, y T) T { return x | y }
+
+// WeightedCount returns the sum of weights of all the bits set to 1, where weights[i] is the weight
+// of the bit at index i. This method will return an error if the number of weights does not match
+// the bitlist length, or if the sum overflows uint64.
+func (b *Bitlist64) WeightedCount(weights []uint64) (uint64, error) {
+	return weightedCount[uint64]("Bitlist64.WeightedCount", b.data, nil, nil, b.size, weights)
+}
+
+// AndWeightedCount returns the sum of weights of all the bits set in the intersection of the two
+// bitlists. This method will return an error if the bitlists are not the same length, if the
+// number of weights does not match the bitlist length, or if the sum overflows uint64.
+func (b *Bitlist64) AndWeightedCount(c *Bitlist64, weights []uint64) (uint64, error) {
+	if b.Len() != c.Len() {
+		return 0, lengthMismatch("Bitlist64.AndWeightedCount", ErrBitlistDifferentLength, b.Len(), c.Len())
+	}
+	return weightedCount("Bitlist64.AndWeightedCount", b.data, c.data, and[uint64], b.size, weights)
+}
+
+// OrWeightedCount returns the sum of weights of all the bits set in the union of the two
+// bitlists. This method will return an error if the bitlists are not the same length, if the
+// number of weights does not match the bitlist length, or if the sum overflows uint64.
+func (b *Bitlist64) OrWeightedCount(c *Bitlist64, weights []uint64) (uint64, error) {
+	if b.Len() != c.Len() {
+		return 0, lengthMismatch("Bitlist64.OrWeightedCount", ErrBitlistDifferentLength, b.Len(), c.Len())
+	}
+	return weightedCount("Bitlist64.OrWeightedCount", b.data, c.data, or[uint64], b.size, weights)
+}
+
+// ParallelWeightedCount is a version of WeightedCount that splits the bitlist into word-aligned
+// chunks, and sums them up concurrently. It is meant for registry-sized bitlists, where a single
+// pass is dominated by the memory access of the weights. If workers is not positive, GOMAXPROCS
+// workers are used.
+func (b *Bitlist64) ParallelWeightedCount(weights []uint64, workers int) (uint64, error) {
+	if uint64(len(weights)) != b.size {
+		return 0, lengthMismatch("Bitlist64.ParallelWeightedCount", ErrWeightsWrongLength, b.size, uint64(len(weights)))
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	wordsPerWorker := (len(b.data) + workers - 1) / workers
+	if workers == 1 || wordsPerWorker == 0 {
+		return b.WeightedCount(weights)
+	}
+
+	type result struct {
+		sum uint64
+		err error
+	}
+	results := make([]result, (len(b.data)+wordsPerWorker-1)/wordsPerWorker)
+	var wg sync.WaitGroup
+	for i := range results {
+		start := i * wordsPerWorker
+		end := min(start+wordsPerWorker, len(b.data))
+		lo := uint64(start) << wordSizeLog2
+		hi := uint64(end) << wordSizeLog2
+		if hi > b.size {
+			hi = b.size
+		}
+		wg.Add(1)
+		go func(res *result, data, weights []uint64, size uint64) {
+			defer wg.Done()
+			res.sum, res.err = weightedCount[uint64]("Bitlist64.ParallelWeightedCount", data, nil, nil, size, weights)
+		}(&results[i], b.data[start:end], weights[lo:hi], hi-lo)
+	}
+	wg.Wait()
+
+	var sum, carry uint64
+	for _, res := range results {
+		if res.err != nil {
+			return 0, res.err
+		}
+		sum, carry = bits.Add64(sum, res.sum, 0)
+		if carry != 0 {
+			return 0, fmt.Errorf("Bitlist64.ParallelWeightedCount: %w", ErrWeightOverflow)
+		}
+	}
+	return sum, nil
+}
+
+// WeightedCount returns the sum of weights of all the bits set to 1, where weights[i] is the weight
+// of the bit at index i. This method will return an error if the number of weights does not match
+// the bitlist length, or if the sum overflows uint64.
+func (b Bitlist) WeightedCount(weights []uint64) (uint64, error) {
+	return weightedCount[uint8]("Bitlist.WeightedCount", b, nil, nil, b.Len(), weights)
+}
+
+// AndWeightedCount returns the sum of weights of all the bits set in the intersection of the two
+// bitlists. This method will return an error if the bitlists are not the same length, if the
+// number of weights does not match the bitlist length, or if the sum overflows uint64.
+func (b Bitlist) AndWeightedCount(c Bitlist, weights []uint64) (uint64, error) {
+	if b.Len() != c.Len() || len(b) != len(c) {
+		return 0, lengthMismatch("Bitlist.AndWeightedCount", ErrBitlistDifferentLength, b.Len(), c.Len())
+	}
+	return weightedCount("Bitlist.AndWeightedCount", b, c, and[uint8], b.Len(), weights)
+}
+
+// OrWeightedCount returns the sum of weights of all the bits set in the union of the two
+// bitlists. This method will return an error if the bitlists are not the same length, if the
+// number of weights does not match the bitlist length, or if the sum overflows uint64.
+func (b Bitlist) OrWeightedCount(c Bitlist, weights []uint64) (uint64, error) {
+	if b.Len() != c.Len() || len(b) != len(c) {
+		return 0, lengthMismatch("Bitlist.OrWeightedCount", ErrBitlistDifferentLength, b.Len(), c.Len())
+	}
+	return weightedCount("Bitlist.OrWeightedCount", b, c, or[uint8], b.Len(), weights)
+}
+
+// WeightedCount returns the sum of weights of all the bits set to 1, where weights[i] is the weight
+// of the bit at index i. This method will return an error if the number of weights does not match
+// the bitvector length, or if the sum overflows uint64.
+func (b Bitvector4) WeightedCount(weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector4.WeightedCount", b, bitvector4ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount[uint8]("Bitvector4.WeightedCount", b, nil, nil, bitvector4BitSize, weights)
+}
+
+// AndWeightedCount returns the sum of weights of all the bits set in the intersection of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector4) AndWeightedCount(c Bitvector4, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector4.AndWeightedCount", b, bitvector4ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector4.AndWeightedCount", c, bitvector4ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector4.AndWeightedCount", b, c, and[uint8], bitvector4BitSize, weights)
+}
+
+// OrWeightedCount returns the sum of weights of all the bits set in the union of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector4) OrWeightedCount(c Bitvector4, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector4.OrWeightedCount", b, bitvector4ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector4.OrWeightedCount", c, bitvector4ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector4.OrWeightedCount", b, c, or[uint8], bitvector4BitSize, weights)
+}
+
+// WeightedCount returns the sum of weights of all the bits set to 1, where weights[i] is the weight
+// of the bit at index i. This method will return an error if the number of weights does not match
+// the bitvector length, or if the sum overflows uint64.
+func (b Bitvector8) WeightedCount(weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector8.WeightedCount", b, bitvector8ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount[uint8]("Bitvector8.WeightedCount", b, nil, nil, bitvector8BitSize, weights)
+}
+
+// AndWeightedCount returns the sum of weights of all the bits set in the intersection of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector8) AndWeightedCount(c Bitvector8, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector8.AndWeightedCount", b, bitvector8ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector8.AndWeightedCount", c, bitvector8ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector8.AndWeightedCount", b, c, and[uint8], bitvector8BitSize, weights)
+}
+
+// OrWeightedCount returns the sum of weights of all the bits set in the union of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector8) OrWeightedCount(c Bitvector8, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector8.OrWeightedCount", b, bitvector8ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector8.OrWeightedCount", c, bitvector8ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector8.OrWeightedCount", b, c, or[uint8], bitvector8BitSize, weights)
+}
+
+// WeightedCount returns the sum of weights of all the bits set to 1, where weights[i] is the weight
+// of the bit at index i. This method will return an error if the number of weights does not match
+// the bitvector length, or if the sum overflows uint64.
+func (b Bitvector32) WeightedCount(weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector32.WeightedCount", b, bitvector32ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount[uint8]("Bitvector32.WeightedCount", b, nil, nil, bitvector32BitSize, weights)
+}
+
+// AndWeightedCount returns the sum of weights of all the bits set in the intersection of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector32) AndWeightedCount(c Bitvector32, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector32.AndWeightedCount", b, bitvector32ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector32.AndWeightedCount", c, bitvector32ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector32.AndWeightedCount", b, c, and[uint8], bitvector32BitSize, weights)
+}
+
+// OrWeightedCount returns the sum of weights of all the bits set in the union of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector32) OrWeightedCount(c Bitvector32, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector32.OrWeightedCount", b, bitvector32ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector32.OrWeightedCount", c, bitvector32ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector32.OrWeightedCount", b, c, or[uint8], bitvector32BitSize, weights)
+}
+
+// WeightedCount returns the sum of weights of all the bits set to 1, where weights[i] is the weight
+// of the bit at index i. This method will return an error if the number of weights does not match
+// the bitvector length, or if the sum overflows uint64.
+func (b Bitvector64) WeightedCount(weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector64.WeightedCount", b, bitvector64ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount[uint8]("Bitvector64.WeightedCount", b, nil, nil, bitvector64BitSize, weights)
+}
+
+// AndWeightedCount returns the sum of weights of all the bits set in the intersection of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector64) AndWeightedCount(c Bitvector64, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector64.AndWeightedCount", b, bitvector64ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector64.AndWeightedCount", c, bitvector64ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector64.AndWeightedCount", b, c, and[uint8], bitvector64BitSize, weights)
+}
+
+// OrWeightedCount returns the sum of weights of all the bits set in the union of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector64) OrWeightedCount(c Bitvector64, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector64.OrWeightedCount", b, bitvector64ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector64.OrWeightedCount", c, bitvector64ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector64.OrWeightedCount", b, c, or[uint8], bitvector64BitSize, weights)
+}
+
+// WeightedCount returns the sum of weights of all the bits set to 1, where weights[i] is the weight
+// of the bit at index i. This method will return an error if the number of weights does not match
+// the bitvector length, or if the sum overflows uint64.
+func (b Bitvector128) WeightedCount(weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector128.WeightedCount", b, bitvector128ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount[uint8]("Bitvector128.WeightedCount", b, nil, nil, bitvector128BitSize, weights)
+}
+
+// AndWeightedCount returns the sum of weights of all the bits set in the intersection of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector128) AndWeightedCount(c Bitvector128, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector128.AndWeightedCount", b, bitvector128ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector128.AndWeightedCount", c, bitvector128ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector128.AndWeightedCount", b, c, and[uint8], bitvector128BitSize, weights)
+}
+
+// OrWeightedCount returns the sum of weights of all the bits set in the union of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector128) OrWeightedCount(c Bitvector128, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector128.OrWeightedCount", b, bitvector128ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector128.OrWeightedCount", c, bitvector128ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector128.OrWeightedCount", b, c, or[uint8], bitvector128BitSize, weights)
+}
+
+// WeightedCount returns the sum of weights of all the bits set to 1, where weights[i] is the weight
+// of the bit at index i. This method will return an error if the number of weights does not match
+// the bitvector length, or if the sum overflows uint64.
+func (b Bitvector256) WeightedCount(weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector256.WeightedCount", b, bitvector256ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount[uint8]("Bitvector256.WeightedCount", b, nil, nil, bitvector256BitSize, weights)
+}
+
+// AndWeightedCount returns the sum of weights of all the bits set in the intersection of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector256) AndWeightedCount(c Bitvector256, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector256.AndWeightedCount", b, bitvector256ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector256.AndWeightedCount", c, bitvector256ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector256.AndWeightedCount", b, c, and[uint8], bitvector256BitSize, weights)
+}
+
+// OrWeightedCount returns the sum of weights of all the bits set in the union of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector256) OrWeightedCount(c Bitvector256, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector256.OrWeightedCount", b, bitvector256ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector256.OrWeightedCount", c, bitvector256ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector256.OrWeightedCount", b, c, or[uint8], bitvector256BitSize, weights)
+}
+
+// WeightedCount returns the sum of weights of all the bits set to 1, where weights[i] is the weight
+// of the bit at index i. This method will return an error if the number of weights does not match
+// the bitvector length, or if the sum overflows uint64.
+func (b Bitvector512) WeightedCount(weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector512.WeightedCount", b, bitvector512ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount[uint8]("Bitvector512.WeightedCount", b, nil, nil, bitvector512BitSize, weights)
+}
+
+// AndWeightedCount returns the sum of weights of all the bits set in the intersection of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector512) AndWeightedCount(c Bitvector512, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector512.AndWeightedCount", b, bitvector512ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector512.AndWeightedCount", c, bitvector512ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector512.AndWeightedCount", b, c, and[uint8], bitvector512BitSize, weights)
+}
+
+// OrWeightedCount returns the sum of weights of all the bits set in the union of the two
+// bitvectors. This method will return an error if the number of weights does not match the
+// bitvector length, or if the sum overflows uint64.
+func (b Bitvector512) OrWeightedCount(c Bitvector512, weights []uint64) (uint64, error) {
+	if err := checkVectorLen("Bitvector512.OrWeightedCount", b, bitvector512ByteSize); err != nil {
+		return 0, err
+	}
+	if err := checkVectorLen("Bitvector512.OrWeightedCount", c, bitvector512ByteSize); err != nil {
+		return 0, err
+	}
+	return weightedCount("Bitvector512.OrWeightedCount", b, c, or[uint8], bitvector512BitSize, weights)
+}
@@ -0,0 +1,195 @@
+package bitfield
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type weightedBitfield interface {
+	Bitfield
+	WeightedCount(weights []uint64) (uint64, error)
+}
+
+// naiveWeightedCount sums the weights of set bits one index at a time.
+func naiveWeightedCount(b Bitfield, weights []uint64) uint64 {
+	sum := uint64(0)
+	for _, idx := range b.BitIndices() {
+		sum += weights[idx]
+	}
+	return sum
+}
+
+func TestWeightedCount(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	bitfields := []weightedBitfield{
+		NewBitlist(0),
+		NewBitlist(13),
+		NewBitlist(300),
+		NewBitlist64(0),
+		NewBitlist64(64),
+		NewBitlist64(300),
+		NewBitvector4(),
+		NewBitvector8(),
+		NewBitvector32(),
+		NewBitvector64(),
+		NewBitvector128(),
+		NewBitvector256(),
+		NewBitvector512(),
+	}
+
+	for _, b := range bitfields {
+		t.Run(fmt.Sprintf("%T(%d)", b, b.Len()), func(t *testing.T) {
+			weights := make([]uint64, b.Len())
+			for i := range weights {
+				weights[i] = uint64(r.Intn(32_000_000_000))
+			}
+
+			if got, err := b.WeightedCount(weights); got != 0 || err != nil {
+				t.Errorf("WeightedCount() on empty bitfield = %d, %v, wanted 0", got, err)
+			}
+			for i := uint64(0); i < b.Len(); i++ {
+				b.SetBitAt(i, r.Intn(2) == 1)
+			}
+			if got, err := b.WeightedCount(weights); got != naiveWeightedCount(b, weights) || err != nil {
+				t.Errorf("WeightedCount() = %d, %v, wanted %d", got, err, naiveWeightedCount(b, weights))
+			}
+
+			if _, err := b.WeightedCount(append(weights, 1)); !errors.Is(err, ErrWeightsWrongLength) {
+				t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWeightsWrongLength, err)
+			}
+		})
+	}
+}
+
+func TestWeightedCount_Bitvector4IgnoresUpperBits(t *testing.T) {
+	b := Bitvector4{0xF1}
+	if got, err := b.WeightedCount([]uint64{1, 2, 4, 8}); got != 1 || err != nil {
+		t.Errorf("WeightedCount() = %d, %v, wanted 1", got, err)
+	}
+}
+
+func TestWeightedCount_Overflow(t *testing.T) {
+	b := NewBitlist64(3)
+	b.SetBitAt(0, true)
+	b.SetBitAt(2, true)
+	weights := []uint64{math.MaxUint64, 1, 1}
+	if _, err := b.WeightedCount(weights); !errors.Is(err, ErrWeightOverflow) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWeightOverflow, err)
+	}
+	if _, err := b.ParallelWeightedCount(weights, 2); !errors.Is(err, ErrWeightOverflow) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWeightOverflow, err)
+	}
+	if _, err := b.ToBitlist().WeightedCount(weights); !errors.Is(err, ErrWeightOverflow) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWeightOverflow, err)
+	}
+
+	// Overflow across chunks summed by different workers.
+	big := NewBitlist64(256)
+	big.SetBitAt(0, true)
+	big.SetBitAt(255, true)
+	weights = make([]uint64, 256)
+	weights[0], weights[255] = math.MaxUint64, 1
+	if _, err := big.ParallelWeightedCount(weights, 4); !errors.Is(err, ErrWeightOverflow) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWeightOverflow, err)
+	}
+}
+
+func TestWeightedCount_AndOr(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []uint64{0, 1, 63, 64, 65, 300} {
+		t.Run(fmt.Sprintf("size:%d", size), func(t *testing.T) {
+			lists := randomBitlists64(r, 2, size)
+			a, b := lists[0], lists[1]
+			weights := make([]uint64, size)
+			for i := range weights {
+				weights[i] = uint64(r.Intn(1000))
+			}
+			and, err := a.And(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			or, err := a.Or(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, err := a.AndWeightedCount(b, weights); got != naiveWeightedCount(and, weights) || err != nil {
+				t.Errorf("AndWeightedCount() = %d, %v, wanted %d", got, err, naiveWeightedCount(and, weights))
+			}
+			if got, err := a.OrWeightedCount(b, weights); got != naiveWeightedCount(or, weights) || err != nil {
+				t.Errorf("OrWeightedCount() = %d, %v, wanted %d", got, err, naiveWeightedCount(or, weights))
+			}
+			if got, err := a.ToBitlist().AndWeightedCount(b.ToBitlist(), weights); got != naiveWeightedCount(and, weights) || err != nil {
+				t.Errorf("Bitlist.AndWeightedCount() = %d, %v, wanted %d", got, err, naiveWeightedCount(and, weights))
+			}
+			if got, err := a.ToBitlist().OrWeightedCount(b.ToBitlist(), weights); got != naiveWeightedCount(or, weights) || err != nil {
+				t.Errorf("Bitlist.OrWeightedCount() = %d, %v, wanted %d", got, err, naiveWeightedCount(or, weights))
+			}
+		})
+	}
+
+	t.Run("bitvectors", func(t *testing.T) {
+		a, b := NewBitvector128(), NewBitvector128()
+		weights := make([]uint64, 128)
+		for i := range weights {
+			weights[i] = uint64(i)
+			a.SetBitAt(uint64(i), i%2 == 0)
+			b.SetBitAt(uint64(i), i%3 == 0)
+		}
+		and, or := uint64(0), uint64(0)
+		for i := uint64(0); i < 128; i++ {
+			if i%2 == 0 && i%3 == 0 {
+				and += i
+			}
+			if i%2 == 0 || i%3 == 0 {
+				or += i
+			}
+		}
+		if got, err := a.AndWeightedCount(b, weights); got != and || err != nil {
+			t.Errorf("AndWeightedCount() = %d, %v, wanted %d", got, err, and)
+		}
+		if got, err := a.OrWeightedCount(b, weights); got != or || err != nil {
+			t.Errorf("OrWeightedCount() = %d, %v, wanted %d", got, err, or)
+		}
+		if _, err := a.AndWeightedCount(Bitvector128{0x01}, weights); !errors.Is(err, ErrWrongLen) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWrongLen, err)
+		}
+	})
+
+	t.Run("check errors", func(t *testing.T) {
+		weights := make([]uint64, 64)
+		if _, err := NewBitlist64(64).AndWeightedCount(NewBitlist64(65), weights); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+		if _, err := NewBitlist(64).OrWeightedCount(NewBitlist(65), weights); !errors.Is(err, ErrBitlistDifferentLength) {
+			t.Errorf("Wrong error returned. Wanted %v, got %v", ErrBitlistDifferentLength, err)
+		}
+	})
+}
+
+func TestBitlist64_ParallelWeightedCount(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []uint64{0, 1, 64, 1000, 100_000} {
+		b := randomBitlists64(r, 1, size)[0]
+		weights := make([]uint64, size)
+		for i := range weights {
+			weights[i] = uint64(r.Intn(32_000_000_000))
+		}
+		want, err := b.WeightedCount(weights)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, workers := range []int{-1, 0, 1, 3, 8, 5000} {
+			if got, err := b.ParallelWeightedCount(weights, workers); got != want || err != nil {
+				t.Errorf("size:%d, ParallelWeightedCount(%d) = %d, %v, wanted %d", size, workers, got, err, want)
+			}
+		}
+	}
+
+	if _, err := NewBitlist64(10).ParallelWeightedCount(make([]uint64, 9), 2); !errors.Is(err, ErrWeightsWrongLength) {
+		t.Errorf("Wrong error returned. Wanted %v, got %v", ErrWeightsWrongLength, err)
+	}
+}